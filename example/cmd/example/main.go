@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/innoai-tech/infra/pkg/cli"
+
+	"example/deploy"
 )
 
 var App = cli.NewApp(
@@ -11,6 +13,7 @@ var App = cli.NewApp(
 	"1.0.0",
 	cli.WithImageNamespace("ghcr.io/octohelm"),
 	cli.WithDeployPreset(true),
+	cli.WithDeployBundle(deploy.Bundle),
 )
 
 func main() {
@@ -21,8 +21,21 @@ import (
 	"example/cmd/example/ui"
 	apiv0 "example/pkg/apis/org/v0"
 	"github.com/innoai-tech/infra/pkg/appinfo"
+	"github.com/innoai-tech/infra/pkg/cli"
 )
 
+func TestAppCommandTree(t *testing.T) {
+	for _, args := range [][]string{
+		{"--help"},
+		{"serve", "--help"},
+		{"webapp", "--help"},
+	} {
+		Must(t, func() error {
+			return cli.Execute(context.Background(), App, args)
+		})
+	}
+}
+
 func TestServeCommandRoundTrip(t *testing.T) {
 	s := &Serve{}
 	s.Server.Addr = "127.0.0.1:0"
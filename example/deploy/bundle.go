@@ -0,0 +1,21 @@
+package deploy
+
+import (
+	"errors"
+
+	infradeploy "github.com/innoai-tech/infra/pkg/deploy"
+)
+
+// Bundle 连接整个应用部署包中的组件，webapp 经前端运行时配置 API_ENDPOINT 访问 example 的 API 服务。
+func Bundle(b *infradeploy.Bundle) error {
+	web, ok := b.Components["webapp"]
+	if !ok {
+		return errors.New("component webapp not found")
+	}
+
+	web.Env["APP_CONFIG__API_ENDPOINT"] = infradeploy.EnvVar{
+		ValueRef: `http://{{ (component "example").Address "http" }}`,
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package webapp
+
+import (
+	"github.com/innoai-tech/infra/pkg/deploy"
+)
+
+// Preset 返回预设的容器部署规格。
+func Preset() *deploy.Container {
+	c := &deploy.Container{
+		Name:      "webapp",
+		ImageName: "ghcr.io/octohelm/example",
+		Version:   "1.0.0",
+		Command:   []string{"example"},
+		Args: []string{
+			"webapp",
+		},
+		Ports: map[string]deploy.Port{
+			"http": {
+				Port:              80,
+				Protocol:          "TCP",
+				Endpoint:          "/",
+				ReadinessEndpoint: "/",
+				LivenessEndpoint:  "/",
+			},
+		},
+		Env: map[string]deploy.EnvVar{
+			// 日志级别
+			// +optional
+			"EXAMPLE_LOG_LEVEL": {
+				Value: "info",
+			},
+			// 日志格式
+			// +optional
+			"EXAMPLE_LOG_FORMAT": {
+				Value: "json",
+			},
+			// 启用后日志经有界队列批量异步输出，避免慢终端或管道阻塞请求
+			// +optional
+			"EXAMPLE_LOG_ASYNC": {
+				Value: "false",
+			},
+			// 异步日志队列长度
+			// +optional
+			// +validate min=0
+			"EXAMPLE_LOG_QUEUE_SIZE": {
+				Value: "2048",
+			},
+			// 异步日志队列满时的处理策略
+			// +optional
+			"EXAMPLE_LOG_DROP_POLICY": {
+				Value: "drop-debug-first",
+			},
+			// 每个周期内同一 (级别, 消息模板, source.func) 保留的前 N 条日志，为 0 时关闭采样
+			// +optional
+			// +validate min=0
+			"EXAMPLE_LOG_SAMPLE_FIRST": {
+				Value: "0",
+			},
+			// 超过前 N 条后每 M 条保留 1 条，小于 0 时全部抑制
+			// +optional
+			"EXAMPLE_LOG_SAMPLE_THEREAFTER": {
+				Value: "0",
+			},
+			// 采样统计周期（秒），每个周期结束时输出被抑制日志的汇总
+			// +optional
+			// +validate min=0
+			"EXAMPLE_LOG_SAMPLE_INTERVAL_SECONDS": {
+				Value: "0",
+			},
+			// 允许对 error 日志采样，默认 error 日志总是输出
+			// +optional
+			"EXAMPLE_LOG_SAMPLE_ERRORS": {
+				Value: "false",
+			},
+			// 设置后将启用 trace 采集，scheme 为 grpc、grpcs、http、https，不带 scheme 时为明文 gRPC
+			// +optional
+			"EXAMPLE_TRACE_COLLECTOR_ENDPOINT": {
+				Value: "",
+			},
+			// trace 采样策略，可选 always、never、ratio:<f>、parentbased_ratio:<f>
+			// +optional
+			"EXAMPLE_TRACE_SAMPLER": {
+				Value: "always",
+			},
+			// 按 http.route 的采样比例，如 /healthz=0,/api/*=0.1，优先于 TraceSampler
+			// +optional
+			"EXAMPLE_TRACE_SAMPLE_RULES": {
+				Value: "",
+			},
+			// 耗时不小于该值（毫秒）的 span 总是上报，小于 0 时关闭
+			// +optional
+			"EXAMPLE_TRACE_SLOW_THRESHOLD_MILLISECONDS": {
+				Value: "1000",
+			},
+			// 出错的 span 按采样策略处理，默认总是上报
+			// +optional
+			"EXAMPLE_TRACE_DROP_ERRORS": {
+				Value: "false",
+			},
+			// 以逗号分隔的 trace 传播格式，可选 tracecontext、baggage、b3、b3single、none
+			// +optional
+			"EXAMPLE_PROPAGATORS": {
+				Value: "tracecontext,baggage,b3",
+			},
+			// 指标采集器地址，格式同 TraceCollectorEndpoint
+			// +optional
+			"EXAMPLE_METRIC_COLLECTOR_ENDPOINT": {
+				Value: "",
+			},
+			// 指标采集间隔（秒）
+			// +optional
+			"EXAMPLE_METRIC_COLLECT_INTERVAL_SECONDS": {
+				Value: "0",
+			},
+			// 设置后将日志同时上报至该采集器，格式同 TraceCollectorEndpoint
+			// +optional
+			"EXAMPLE_LOG_COLLECTOR_ENDPOINT": {
+				Value: "",
+			},
+			// 校验采集器证书的 CA 文件，用于 grpcs 与 https
+			// +optional
+			"EXAMPLE_COLLECTOR_TLSCA_FILE": {
+				Value: "",
+			},
+			// 上报采集器的客户端证书文件
+			// +optional
+			"EXAMPLE_COLLECTOR_TLS_CERT_FILE": {
+				Value: "",
+			},
+			// 上报采集器的客户端私钥文件
+			// +optional
+			"EXAMPLE_COLLECTOR_TLS_KEY_FILE": {
+				Value: "",
+			},
+			// 上报采集器的请求头，如 authorization=Bearer%20xxx，多个以逗号分隔
+			// +optional
+			"EXAMPLE_COLLECTOR_HEADERS": {
+				Value:  "",
+				Secret: true,
+			},
+			// 上报压缩方式，可选 none、gzip
+			// +optional
+			"EXAMPLE_COLLECTOR_COMPRESSION": {
+				Value: "none",
+			},
+			// 上报超时（秒）
+			// +optional
+			// +validate min=0
+			"EXAMPLE_COLLECTOR_TIMEOUT_SECONDS": {
+				Value: "3",
+			},
+			// +optional
+			"EXAMPLE_ENV": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_VER": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_BASE_HREF": {
+				Value: "/",
+			},
+			// +optional
+			"EXAMPLE_CONFIG": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_DISABLE_HISTORY_FALLBACK": {
+				Value: "false",
+			},
+			// +optional
+			"EXAMPLE_DISABLE_CSP": {
+				Value: "false",
+			},
+			// +optional
+			"EXAMPLE_ROOT": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_TLS_CERT_FILE": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_TLS_KEY_FILE": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_TLS_CLIENT_CA_FILE": {
+				Value: "",
+			},
+			// +optional
+			"EXAMPLE_TLS_CLIENT_AUTH": {
+				Value: "",
+			},
+			"EXAMPLE_ADDR": {
+				ValueRef: `:{{ .Ports["http"].Port }}`,
+			},
+		},
+		Healthcheck: []string{
+			"example",
+			"probe",
+			"http://127.0.0.1:80/",
+		},
+	}
+
+	return c
+}
@@ -30,6 +30,7 @@ type worker struct {
 type Agent struct {
 	kind    string
 	done    chan struct{}
+	started chan struct{}
 	closed  atomic.Bool
 	serving atomic.Bool
 	wg      sync.WaitGroup
@@ -52,6 +53,7 @@ func (x *Agent) Init(ctx context.Context) error {
 	}
 
 	x.done = make(chan struct{})
+	x.started = make(chan struct{})
 	return nil
 }
 
@@ -106,9 +108,29 @@ func (x *Agent) Serve(pctx context.Context) error {
 		})
 	}
 
+	if x.started != nil {
+		close(x.started)
+	}
+
 	return eg.Wait()
 }
 
+// WaitReady 等待所有 worker 完成启动；没有 worker 时直接返回。
+func (x *Agent) WaitReady(ctx context.Context) error {
+	if x.Disabled(ctx) || x.started == nil {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-x.done:
+		return nil
+	case <-x.started:
+		return nil
+	}
+}
+
 // Shutdown 停止所有 worker 并等待其退出。
 func (x *Agent) Shutdown(ctx context.Context) error {
 	if x.closed.Swap(true) {
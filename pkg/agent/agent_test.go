@@ -136,6 +136,45 @@ func TestServeInjectsContextAndShutdownCancels(t *testing.T) {
 	)
 }
 
+func TestWaitReadyAfterServe(t *testing.T) {
+	t.Parallel()
+
+	a := &Agent{}
+
+	Must(t, func() error {
+		return a.Init(context.Background())
+	})
+
+	a.Host("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	serveDone := make(chan error, 1)
+	go func() {
+		serveDone <- a.Serve(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	Then(
+		t, "Serve 启动全部 worker 后 WaitReady 返回",
+		ExpectDo(func() error {
+			return a.WaitReady(ctx)
+		}),
+	)
+
+	Must(t, func() error {
+		return a.Shutdown(context.Background())
+	})
+
+	Then(
+		t, "关闭后 Serve 正常退出",
+		Expect(<-serveDone, Equal(error(nil))),
+	)
+}
+
 func TestServeReturnsWorkerError(t *testing.T) {
 	t.Parallel()
 
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/innoai-tech/infra/pkg/appinfo"
 	"github.com/innoai-tech/infra/pkg/cli/internal"
 	"github.com/innoai-tech/infra/pkg/configuration"
+	"github.com/innoai-tech/infra/pkg/deploy"
 )
 
 // AppOptionFunc 用于修改应用级元数据。
@@ -33,6 +35,22 @@ func WithDeployPreset(v bool) AppOptionFunc {
 	}
 }
 
+// WithDeployBundle 注册连接部署包中组件的函数。
+//
+// deploy-bundle 以命令树中全部组件构造部署包后调用该函数，通常为组件设置引用其他组件的 EnvVar.ValueRef，随后校验引用并导出整个应用的部署文件。
+func WithDeployBundle(fn func(b *deploy.Bundle) error) AppOptionFunc {
+	return func(a *app) {
+		a.deployBundle = fn
+	}
+}
+
+// WithSignalPolicy 设置服务运行期间的信号处理策略，例如 configuration.ReloadOnHangup()。
+func WithSignalPolicy(policy configuration.SignalPolicy) AppOptionFunc {
+	return func(a *app) {
+		a.signalPolicy = policy
+	}
+}
+
 // NewApp 创建一个新的 CLI 应用根命令。
 func NewApp(name string, version string, fns ...AppOptionFunc) Command {
 	a := &app{
@@ -59,7 +77,9 @@ func NewApp(name string, version string, fns ...AppOptionFunc) Command {
 
 // ParseArgs 解析命令行参数。
 func (a *app) ParseArgs(args []string) {
+	a.commands = map[*cobra.Command]*C{}
 	a.root = a.newFrom(a, nil)
+	a.addBuiltinCommands(a.root)
 	a.root.SetArgs(args)
 }
 
@@ -74,6 +94,9 @@ type app struct {
 	root         *cobra.Command
 	version      string
 	deployPreset bool
+	deployBundle func(b *deploy.Bundle) error
+	signalPolicy configuration.SignalPolicy
+	commands     map[*cobra.Command]*C
 }
 
 func (a *app) newFrom(cc Command, parent Command) *cobra.Command {
@@ -84,7 +107,15 @@ func (a *app) newFrom(cc Command, parent Command) *cobra.Command {
 		Version: a.version,
 	}
 
-	a.bindCommand(cmd, c, cc)
+	bindErr := a.bindCommand(cmd, c, cc)
+
+	if a.commands != nil {
+		a.commands[cmd] = c
+	}
+
+	for _, f := range c.flagVars {
+		registerFlagCompletion(cmd, f)
+	}
 
 	for _, arg := range c.args {
 		if arg.Interspersed {
@@ -103,19 +134,47 @@ func (a *app) newFrom(cc Command, parent Command) *cobra.Command {
 
 	dumpK8s := false
 	dumpDeployPreset := false
+	deployExport := make([]string, 0)
 	showConfiguration := false
+	dumpConfigSchema := false
+	envFile := ""
+	configFile := ""
+
+	// 内置 flag 与 singleton 的 flag 同名时跳过，由 singleton 的 flag 生效
+	builtinFlags := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+
+	builtinFlags.BoolVarP(&showConfiguration, "list-configuration", "c", os.Getenv("ENV") == "DEV", "显示配置信息及其来源")
+	builtinFlags.BoolVarP(&dumpConfigSchema, "dump-config-schema", "", false, "以 JSON Schema 导出配置项定义")
+	builtinFlags.StringVarP(&envFile, "env-file", "", "", "从 env 文件读取配置，优先级低于环境变量，重载时会重新读取")
+	builtinFlags.StringVarP(&configFile, "config-file", "", "", "从配置文件（YAML/JSON/TOML/dotenv）读取配置，优先级：flag > 环境变量 > 配置文件 > 默认值")
 
-	cmd.Flags().BoolVarP(&showConfiguration, "list-configuration", "c", os.Getenv("ENV") == "DEV", "显示配置信息")
+	_ = cobra.MarkFlagFilename(builtinFlags, "env-file")
+	_ = cobra.MarkFlagFilename(builtinFlags, "config-file", "yaml", "yml", "json", "toml", "env")
+
+	if a.deployPreset && parent == nil {
+		addBuiltinCommand(cmd, a.newDeployBundleCommand())
+	}
 
 	if c.info.Component != nil {
 		if a.deployPreset {
-			cmd.Flags().BoolVarP(&dumpDeployPreset, "deploy-preset", "", false, "导出部署预设为 Go 源码")
+			builtinFlags.BoolVarP(&dumpDeployPreset, "deploy-preset", "", false, "导出部署预设为 Go 源码")
+			builtinFlags.StringSliceVarP(&deployExport, "deploy-export", "", nil, "随部署预设一并导出 compose 或 systemd 部署文件 (ALLOW VALUES: compose, systemd)")
 		} else {
-			cmd.Flags().BoolVarP(&dumpK8s, "dump-k8s", "", false, "导出 k8s 组件配置（已弃用，请使用 --deploy-preset）")
+			builtinFlags.BoolVarP(&dumpK8s, "dump-k8s", "", false, "导出 k8s 组件配置（已弃用，请使用 --deploy-preset）")
 		}
 	}
 
+	cmd.Flags().AddFlagSet(builtinFlags)
+
+	if f := builtinFlags.Lookup("deploy-export"); f != nil && cmd.Flags().Lookup(f.Name) == f {
+		_ = cmd.RegisterFlagCompletionFunc(f.Name, cobra.FixedCompletions([]string{"compose", "systemd"}, cobra.ShellCompDirectiveNoFileComp))
+	}
+
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if bindErr != nil {
+			return bindErr
+		}
+
 		if err := c.args.Validate(args); err != nil {
 			return err
 		}
@@ -126,40 +185,79 @@ func (a *app) newFrom(cc Command, parent Command) *cobra.Command {
 			return c.dumpK8sConfiguration(ctx, "./cuepkg/component")
 		}
 
-		if dumpDeployPreset {
-			return c.dumpDeployPreset(ctx, "./deploy")
+		if dumpDeployPreset || len(deployExport) > 0 {
+			if err := c.dumpDeployPreset(ctx, "./deploy"); err != nil {
+				return err
+			}
+			return c.dumpDeployExport("./deploy", deployExport)
 		}
 
-		envVars := internal.EnvVarsFromEnviron(os.Environ())
+		if dumpConfigSchema {
+			return c.dumpConfigSchema(cmd.OutOrStdout())
+		}
+
+		sources, err := loadConfigSources(envFile, configFile, c.flagVars)
+		if err != nil {
+			return err
+		}
 
 		for i := range c.flagVars {
 			f := c.flagVars[i]
 
-			if err := f.FromEnvVars(envVars); err != nil {
+			if err := f.Bind(sources...); err != nil {
 				return err
 			}
 
+			resolved, ref, err := resolveSecret(ctx, f, f.Value)
+			if err != nil {
+				return err
+			}
+			if ref != "" {
+				f.Value.Set(resolved)
+				f.SecretRef = ref
+			}
+
 			if showConfiguration {
 				fmt.Println(f.Info())
 			}
 		}
 
+		errs := make([]error, 0)
+
 		for i := range c.flagVars {
 			f := c.flagVars[i]
 
-			if f.Required && f.Value.IsZero() {
-				return fmt.Errorf("缺失必填配置 ${%s}, 可通过环境变量或 --%s 设置", f.EnvVar, f.Name)
+			if err := f.Validate(f.Value); err != nil {
+				errs = append(errs, err)
 			}
 		}
 
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+
 		singletons := append(
-			configuration.Singletons{{
-				Configurator: &c.info,
-			}},
+			configuration.Singletons{
+				{
+					Configurator: &c.info,
+				},
+				{
+					// 先于其他 Reloader 执行，使它们重载时读到更新后的配置
+					Configurator: &configurationReloader{
+						envFile:    envFile,
+						configFile: configFile,
+						flagVars:   c.flagVars,
+					},
+				},
+			},
 			c.singletons...,
 		)
 
-		ctx, err := singletons.Init(ctx)
+		if a.signalPolicy != nil {
+			ctx = configuration.SignalPolicyInjectContext(ctx, a.signalPolicy)
+		}
+
+		ctx, err = singletons.Init(ctx)
 		if err != nil {
 			return err
 		}
@@ -170,7 +268,7 @@ func (a *app) newFrom(cc Command, parent Command) *cobra.Command {
 	return cmd
 }
 
-func (a *app) bindCommand(c *cobra.Command, info *C, v any) {
+func (a *app) bindCommand(c *cobra.Command, info *C, v any) error {
 	rv := reflect.ValueOf(v)
 
 	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
@@ -183,7 +281,7 @@ func (a *app) bindCommand(c *cobra.Command, info *C, v any) {
 		info.info.Name = strings.ToLower(rv.Type().Name())
 	}
 
-	a.bindCommandFromStruct(info, rv, c.Flags())
+	err := a.bindCommandFromStruct(info, rv, c.Flags())
 
 	if len(info.args) > 0 {
 		c.Use = fmt.Sprintf("%s [flags] %s", info.info.Name, info.args)
@@ -192,9 +290,11 @@ func (a *app) bindCommand(c *cobra.Command, info *C, v any) {
 	}
 
 	c.Short = info.info.Desc
+
+	return err
 }
 
-func (a *app) bindCommandFromStruct(c *C, rv reflect.Value, flags *pflag.FlagSet) {
+func (a *app) bindCommandFromStruct(c *C, rv reflect.Value, flags *pflag.FlagSet) error {
 	st := rv.Type()
 
 	if v, ok := rv.Interface().(CanRuntimeDoc); ok {
@@ -237,6 +337,10 @@ func (a *app) bindCommandFromStruct(c *C, rv reflect.Value, flags *pflag.FlagSet
 	c.singletons = configuration.SingletonsFromStruct(rv)
 
 	for _, s := range c.singletons {
-		addConfigurator(c, flags, s.Configurator, s.Name, a.info.Name)
+		if err := addConfigurator(c, flags, s.Configurator, s.Name, a.info.Name); err != nil {
+			return fmt.Errorf("bind flags of command %s failed: %w", c.info.Name, err)
+		}
 	}
+
+	return nil
 }
@@ -3,11 +3,19 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -16,6 +24,7 @@ import (
 	. "github.com/octohelm/x/testing/v2"
 
 	"github.com/innoai-tech/infra/pkg/cli/internal"
+	"github.com/innoai-tech/infra/pkg/deploy"
 )
 
 type runtimeDocValue struct{}
@@ -221,7 +230,7 @@ func TestExecuteCommandLifecycle(t *testing.T) {
 	)
 }
 
-func TestExecuteCommandEnvOverridesFlagValue(t *testing.T) {
+func TestExecuteCommandFlagOverridesEnvValue(t *testing.T) {
 	t.Setenv("DEMO_VALUE", "from-env")
 
 	app := NewApp("demo", "1.0.0").(*app)
@@ -232,8 +241,85 @@ func TestExecuteCommandEnvOverridesFlagValue(t *testing.T) {
 	})
 
 	Then(
-		t, "环境变量会覆盖命令行 flag 值",
-		Expect(cmd.ExecSingleton.Value, Equal("from-env")),
+		t, "命令行 flag 值优先于环境变量",
+		Expect(cmd.ExecSingleton.Value, Equal("from-flag")),
+	)
+}
+
+func TestExecuteCommandConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("mode: from-file\nsecret: token\n"), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	t.Setenv("DEMO_SECRET", "from-env")
+
+	app := NewApp("demo", "1.0.0").(*app)
+	cmd := AddTo(app, &listConfigCommand{})
+
+	output := captureStdout(t, func() {
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"inspect", "--config-file", configFile, "--list-configuration"})
+		})
+	})
+
+	Then(
+		t, "配置文件优先级低于环境变量，list-configuration 展示值来源",
+		Expect(cmd.Mode, Equal("from-file")),
+		Expect(cmd.Secret, Equal("from-env")),
+		Expect(strings.Contains(output, "DEMO_MODE = from-file # file"), Equal(true)),
+		Expect(strings.Contains(output, "DEMO_SECRET = -------- # env"), Equal(true)),
+	)
+}
+
+func TestExecuteCommandConfigFileUnknownKeys(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"mode":"x","unknown":1}`), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &listConfigCommand{})
+
+	err := Execute(context.Background(), app, []string{"inspect", "--config-file", configFile})
+
+	Then(
+		t, "配置文件中的未知键会报错",
+		Expect(err != nil && strings.Contains(err.Error(), "unknown keys in config file"), Equal(true)),
+	)
+}
+
+type ConfigFlagOptions struct {
+	Config string `flag:",omitzero"`
+}
+
+func (o *ConfigFlagOptions) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type configFlagCommand struct {
+	C `name:"webapp"`
+	ConfigFlagOptions
+	ListConfigOptions
+}
+
+func TestExecuteCommandWithConfigFlag(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("mode: from-file\n"), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	app := NewApp("demo", "1.0.0").(*app)
+	cmd := AddTo(app, &configFlagCommand{})
+
+	Must(t, func() error {
+		return Execute(context.Background(), app, []string{"webapp", "--config", "{}", "--config-file", configFile})
+	})
+
+	Then(
+		t, "singleton 的 --config 与内置的 --config-file 互不冲突",
+		Expect(cmd.Config, Equal("{}")),
+		Expect(cmd.Mode, Equal("from-file")),
 	)
 }
 
@@ -259,6 +345,509 @@ func TestListConfigurationOutput(t *testing.T) {
 	)
 }
 
+type ReloadableOptions struct {
+	Level string `flag:",omitzero,reload"`
+	Mode  string `flag:",omitzero,reload"`
+	Token string `flag:",omitzero,secret"`
+}
+
+func (o *ReloadableOptions) Reload(ctx context.Context) error {
+	return nil
+}
+
+type StaticOptions struct {
+	Addr string `flag:",omitzero"`
+}
+
+type reloadCommand struct {
+	C
+	ReloadableOptions
+	StaticOptions
+}
+
+func TestConfigurationReloader(t *testing.T) {
+	cmd := &reloadCommand{}
+	cmd.Level = "info"
+	cmd.Addr = ":80"
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addConfigurator(&cmd.C, flags, &cmd.ReloadableOptions, "", "demo")
+	addConfigurator(&cmd.C, flags, &cmd.StaticOptions, "", "demo")
+
+	for _, f := range cmd.flagVars {
+		Must(t, func() error {
+			return f.Bind()
+		})
+	}
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("DEMO_LEVEL=debug\nDEMO_MODE=prod\nDEMO_TOKEN=secret\n"), 0o600); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+	t.Setenv("DEMO_ADDR", ":8080")
+
+	r := &configurationReloader{
+		envFile:  envFile,
+		flagVars: cmd.flagVars,
+	}
+
+	Must(t, func() error {
+		return r.Reload(context.Background())
+	})
+
+	Then(
+		t, "重载只应用到支持热更新的配置对象中标记了 reload 的字段",
+		Expect(cmd.Level, Equal("debug")),
+		Expect(cmd.Mode, Equal("prod")),
+		Expect(cmd.Token, Equal("")),
+		Expect(cmd.Addr, Equal(":80")),
+	)
+
+	if err := os.WriteFile(envFile, []byte("DEMO_LEVEL=debug\n"), 0o600); err != nil {
+		t.Fatalf("write env file: %v", err)
+	}
+
+	Must(t, func() error {
+		return r.Reload(context.Background())
+	})
+
+	Then(
+		t, "env 文件中移除的配置回退到启动时的值",
+		Expect(cmd.Level, Equal("debug")),
+		Expect(cmd.Mode, Equal("")),
+	)
+}
+
+type ValidatedOptions struct {
+	Port int    `flag:",omitzero" validate:"min=1,max=65535"`
+	Mode string `flag:",omitzero" validate:"oneof=dev prod"`
+}
+
+func (o *ValidatedOptions) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type validatedCommand struct {
+	C `name:"check"`
+	ValidatedOptions
+}
+
+func TestExecuteCommandValidation(t *testing.T) {
+	t.Setenv("DEMO_PORT", "70000")
+	t.Setenv("DEMO_MODE", "test")
+
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &validatedCommand{})
+
+	err := Execute(context.Background(), app, []string{"check"})
+
+	Then(
+		t, "所有校验失败会合并为一个错误返回",
+		Expect(err != nil, Equal(true)),
+		Expect(strings.Contains(err.Error(), "${DEMO_PORT}"), Equal(true)),
+		Expect(strings.Contains(err.Error(), "${DEMO_MODE}"), Equal(true)),
+	)
+}
+
+type MalformedOptions struct {
+	Port int `flag:",omitzero" validate:"min=one"`
+}
+
+func (o *MalformedOptions) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type malformedCommand struct {
+	C `name:"broken"`
+	MalformedOptions
+}
+
+func TestExecuteCommandMalformedValidateTag(t *testing.T) {
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &malformedCommand{})
+
+	err := Execute(context.Background(), app, []string{"broken"})
+
+	Then(
+		t, "无效的 validate tag 在执行时返回错误",
+		Expect(err != nil, Equal(true)),
+		Expect(strings.Contains(err.Error(), "MalformedOptions.Port"), Equal(true)),
+	)
+}
+
+func TestFlagUsageShowsValidateRules(t *testing.T) {
+	t.Parallel()
+
+	c := &C{}
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	collectFlagsFromConfigurator(c, flags, reflect.ValueOf(&validatedCommand{}).Elem(), "", "APP_", "")
+
+	Then(
+		t, "flag 说明展示校验规则",
+		Expect(strings.Contains(flags.Lookup("port").Usage, "(VALIDATE: min=1,max=65535)"), Equal(true)),
+	)
+}
+
+func TestExecuteCommandSecretReference(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("write secret file: %v", err)
+	}
+
+	t.Setenv("DEMO_SECRET", "file://"+secretFile)
+	t.Setenv("DEMO_MODE", "env:OTHER_MODE")
+	t.Setenv("OTHER_MODE", "plain")
+
+	app := NewApp("demo", "1.0.0").(*app)
+	cmd := AddTo(app, &listConfigCommand{})
+
+	output := captureStdout(t, func() {
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"inspect", "--list-configuration"})
+		})
+	})
+
+	lines := parseInfoLines(output)
+
+	Then(
+		t, "secret 字段的引用在绑定时解析，展示时只显示引用",
+		Expect(cmd.Secret, Equal("from-file")),
+		Expect(lines["DEMO_SECRET"], Equal("file://"+secretFile)),
+		Expect(strings.Contains(output, "from-file"), Equal(false)),
+		Expect(cmd.Mode, Equal("env:OTHER_MODE")),
+	)
+}
+
+func TestRegisterSecretResolver(t *testing.T) {
+	RegisterSecretResolver("vault", SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		return "resolved:" + ref.Host + ref.Path, nil
+	}))
+
+	f := &internal.FlagVar{
+		EnvVar: "DEMO_TOKEN",
+		Secret: true,
+		Value:  reflect.New(reflect.TypeFor[string]()).Elem(),
+	}
+	f.Value.SetString("vault://kv/db")
+
+	resolved, ref, err := resolveSecret(context.Background(), f, f.Value)
+
+	Then(
+		t, "可注册自定义 scheme 的解析器",
+		Expect(err, Equal(error(nil))),
+		Expect(resolved.String(), Equal("resolved:kv/db")),
+		Expect(ref, Equal("vault://kv/db")),
+	)
+}
+
+type outputFormat string
+
+func (outputFormat) EnumValues() []any {
+	return []any{"json", "yaml"}
+}
+
+type FormatOptions struct {
+	Format outputFormat `flag:",omitzero"`
+}
+
+func (o *FormatOptions) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type formatCommand struct {
+	C `name:"show"`
+	FormatOptions
+}
+
+func TestCompletionCommand(t *testing.T) {
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &validatedCommand{})
+
+	output := captureStdout(t, func() {
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"completion", "bash"})
+		})
+	})
+
+	Then(
+		t, "completion 子命令输出对应 shell 的补全脚本",
+		Expect(strings.Contains(output, "__start_demo"), Equal(true)),
+	)
+}
+
+func TestProbeCommand(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/ready" {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	check := func(path string) error {
+		app := NewApp("demo", "1.0.0").(*app)
+		return Execute(context.Background(), app, []string{"probe", srv.URL + path})
+	}
+
+	Then(
+		t, "probe 子命令在服务就绪时成功，否则返回错误",
+		ExpectDo(func() error { return check("/ready") }),
+		Expect(check("/other") != nil, Equal(true)),
+	)
+}
+
+func TestEnumFlagCompletion(t *testing.T) {
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &formatCommand{})
+
+	output := captureStdout(t, func() {
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{cobra.ShellCompRequestCmd, "show", "--format", ""})
+		})
+	})
+
+	Then(
+		t, "枚举 flag 补全候选值",
+		Expect(strings.Contains(output, "json\nyaml\n"), Equal(true)),
+	)
+}
+
+func TestGenDocsCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &validatedCommand{})
+
+	Must(t, func() error {
+		return Execute(context.Background(), app, []string{"gen-docs", "-o", dir})
+	})
+
+	markdown := string(MustValue(t, func() ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, "demo_check.md"))
+	}))
+	man := string(MustValue(t, func() ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, "man1", "demo-check.1"))
+	}))
+
+	Then(
+		t, "gen-docs 为每个子命令生成 Markdown 与 man 手册",
+		Expect(strings.Contains(markdown, "| `--port` | `DEMO_PORT` |"), Equal(true)),
+		Expect(strings.Contains(markdown, "校验: min=1,max=65535"), Equal(true)),
+		Expect(strings.HasPrefix(man, `.TH "DEMO-CHECK" "1"`), Equal(true)),
+		Expect(strings.Contains(man, `\fB\-\-port\fP`), Equal(true)),
+		Expect(strings.Contains(man, `Env: \fIDEMO_PORT\fP`), Equal(true)),
+	)
+}
+
+type SchemaOptions struct {
+	Endpoint string        `flag:"" validate:"url"`
+	Port     int           `flag:",omitzero" validate:"min=1,max=65535"`
+	Tags     []string      `flag:",omitzero"`
+	Token    string        `flag:",omitzero,secret"`
+	Addr     string        `flag:",omitzero,expose=http"`
+	Timeout  time.Duration `flag:",omitzero"`
+}
+
+func (o *SchemaOptions) SetDefaults() {
+	if o.Port == 0 {
+		o.Port = 80
+	}
+}
+
+func (o *SchemaOptions) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type schemaCommand struct {
+	C `name:"serve"`
+	SchemaOptions
+}
+
+func TestDumpConfigSchema(t *testing.T) {
+	app := NewApp("demo", "1.0.0").(*app)
+	_ = AddTo(app, &schemaCommand{})
+
+	output := captureStdout(t, func() {
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"serve", "--dump-config-schema"})
+		})
+	})
+
+	schema := &configSchema{}
+	Must(t, func() error {
+		return json.Unmarshal([]byte(output), schema)
+	})
+
+	Then(
+		t, "按环境变量名导出每个配置项的 JSON Schema",
+		Expect(schema.Type, Equal("object")),
+		Expect(schema.Required, Equal([]string{"DEMO_ENDPOINT"})),
+		Expect(schema.Properties["DEMO_ENDPOINT"].Format, Equal("uri")),
+		Expect(schema.Properties["DEMO_PORT"].Type, Equal("integer")),
+		Expect(schema.Properties["DEMO_PORT"].Default, Equal(any(float64(80)))),
+		Expect(*schema.Properties["DEMO_PORT"].Maximum, Equal(float64(65535))),
+		Expect(schema.Properties["DEMO_TAGS"].Items.Type, Equal("string")),
+		Expect(schema.Properties["DEMO_TOKEN"].Secret, Equal(true)),
+		Expect(schema.Properties["DEMO_ADDR"].Expose, Equal("http")),
+		Expect(schema.Properties["DEMO_TIMEOUT"].Format, Equal("x-go-duration")),
+		Expect(regexp.MustCompile(schema.Properties["DEMO_TIMEOUT"].Pattern).MatchString("1m30s"), Equal(true)),
+		Expect(regexp.MustCompile(schema.Properties["DEMO_TIMEOUT"].Pattern).MatchString("PT1M30S"), Equal(false)),
+	)
+}
+
+type DeployServer struct {
+	Addr     string `flag:",omitzero,expose=http"`
+	Token    string `flag:",omitzero,secret"`
+	DataDir  string `flag:",omitzero,volume"`
+	CertFile string `flag:",omitzero,volume=secret"`
+	KeyFile  string `flag:",omitzero,volume=secret"`
+}
+
+func (s *DeployServer) SetDefaults() {
+	if s.Addr == "" {
+		s.Addr = ":8080"
+	}
+	if s.DataDir == "" {
+		s.DataDir = "/var/lib/demo"
+	}
+	if s.CertFile == "" {
+		s.CertFile = "/etc/demo/tls/tls.crt"
+	}
+	if s.KeyFile == "" {
+		s.KeyFile = "/etc/demo/tls/tls.key"
+	}
+}
+
+func (s *DeployServer) InjectContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+type deployCommand struct {
+	C `name:"serve" component:"demo-server,kind=StatefulSet,cpu=100m/500m,memory=128Mi,readonly"`
+	DeployServer
+}
+
+func TestDeployContainer(t *testing.T) {
+	app := NewApp("demo", "1.0.0", WithImageNamespace("ghcr.io/demo")).(*app)
+	cmd := AddTo(app, &deployCommand{})
+
+	app.ParseArgs([]string{"serve"})
+
+	container := MustValue(t, func() (*deploy.Container, error) {
+		return cmd.Cmd().deployContainer()
+	})
+
+	addr := MustValue(t, func() (string, error) {
+		return container.Env["DEMO_ADDR"].ToValue(container)
+	})
+
+	Then(
+		t, "按组件信息与 flag 构造部署规格，expose 值引用端口模板",
+		Expect(container.Name, Equal("demo-server")),
+		Expect(container.Kind, Equal("StatefulSet")),
+		Expect(container.Image(), Equal("ghcr.io/demo/demo:1.0.0")),
+		Expect(container.Ports["http"].Port, Equal(8080)),
+		Expect(addr, Equal(":8080")),
+		Expect(container.Env["DEMO_TOKEN"].Secret, Equal(true)),
+		Expect(container.Healthcheck, Equal([]string{"demo", "probe", "http://127.0.0.1:8080/"})),
+	)
+
+	Then(
+		t, "volume flag 生成挂载，文件 flag 只读挂载所在目录，组件 tag 选项生成资源与安全设置",
+		Expect(container.Mounts, Equal([]deploy.Mount{
+			{Volume: "data-dir", MountPath: "/var/lib/demo"},
+			{Volume: "cert-file", MountPath: "/etc/demo/tls", ReadOnly: true},
+		})),
+		Expect(container.Volumes["data-dir"].Type, Equal(deploy.VolumeEmptyDir)),
+		Expect(container.Volumes["cert-file"], Equal(deploy.Volume{Type: deploy.VolumeSecret, Source: "demo-server-cert-file"})),
+		Expect(*container.Resources, Equal(deploy.Resources{
+			Requests: deploy.ResourceList{CPU: "100m", Memory: "128Mi"},
+			Limits:   deploy.ResourceList{CPU: "500m"},
+		})),
+		Expect(container.SecurityContext.ReadOnlyRootFilesystem, Equal(true)),
+	)
+}
+
+type deployWebCommand struct {
+	C `name:"web" component:"demo-web"`
+	DeployServer
+}
+
+func TestDeployBundleCommand(t *testing.T) {
+	connect := func(b *deploy.Bundle) error {
+		web, ok := b.Components["demo-web"]
+		if !ok {
+			return errors.New("component demo-web not found")
+		}
+		web.Env["DEMO_API_ENDPOINT"] = deploy.EnvVar{ValueRef: `http://{{ (component "demo-server").Address "http" }}`}
+		return nil
+	}
+
+	t.Run("以全部组件构造部署包", func(t *testing.T) {
+		dir := t.TempDir()
+
+		app := NewApp("demo", "1.0.0", WithImageNamespace("ghcr.io/demo"), WithDeployPreset(true), WithDeployBundle(connect)).(*app)
+		_ = AddTo(app, &deployCommand{})
+		_ = AddTo(app, &deployWebCommand{})
+
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"deploy-bundle", "-o", dir, "--format", "compose"})
+		})
+
+		compose := string(MustValue(t, func() ([]byte, error) {
+			return os.ReadFile(filepath.Join(dir, "compose.yaml"))
+		}))
+
+		preset := string(MustValue(t, func() ([]byte, error) {
+			return os.ReadFile(filepath.Join(dir, "demo_web", "container.go"))
+		}))
+
+		Then(
+			t, "一次导出全部组件的预设，部署包与预设一致，组件间引用解析为服务地址",
+			Expect(strings.Contains(preset, "Secret: true,"), Equal(true)),
+			Expect(strings.Contains(preset, `"probe",`), Equal(true)),
+			Expect(strings.Contains(compose, "  demo-server:\n"), Equal(true)),
+			Expect(strings.Contains(compose, "image: ghcr.io/demo/demo:1.0.0\n"), Equal(true)),
+			Expect(strings.Contains(compose, "  demo-web:\n"), Equal(true)),
+			Expect(strings.Contains(compose, "DEMO_API_ENDPOINT: http://demo-server:8080"), Equal(true)),
+		)
+	})
+
+	t.Run("未注册连接函数", func(t *testing.T) {
+		dir := t.TempDir()
+
+		app := NewApp("demo", "1.0.0", WithDeployPreset(true)).(*app)
+		_ = AddTo(app, &deployCommand{})
+
+		Must(t, func() error {
+			return Execute(context.Background(), app, []string{"deploy-bundle", "-o", dir, "--format", "compose"})
+		})
+
+		Then(
+			t, "仍以全部组件导出预设与整个应用的部署文件",
+			Expect(fileExists(filepath.Join(dir, "demo_server", "container.go")), Equal(true)),
+			Expect(fileExists(filepath.Join(dir, "compose.yaml")), Equal(true)),
+		)
+	})
+
+	t.Run("组件间引用无法解析", func(t *testing.T) {
+		app := NewApp("demo", "1.0.0", WithDeployPreset(true), WithDeployBundle(connect)).(*app)
+		_ = AddTo(app, &deployWebCommand{})
+
+		err := Execute(context.Background(), app, []string{"deploy-bundle", "-o", t.TempDir()})
+
+		Then(
+			t, "校验失败并指出组件",
+			Expect(err != nil && strings.Contains(err.Error(), "component demo-web"), Equal(true)),
+		)
+	})
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
 func captureStdout(t *testing.T, fn func()) string {
 	t.Helper()
 
@@ -295,6 +884,7 @@ func parseInfoLines(output string) map[string]string {
 		if !ok {
 			continue
 		}
+		value, _, _ = strings.Cut(value, " # ")
 		lines[strings.TrimSpace(key)] = strings.TrimSpace(value)
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/innoai-tech/infra/pkg/appinfo"
 	"github.com/innoai-tech/infra/pkg/cli/internal"
 	"github.com/innoai-tech/infra/pkg/configuration"
+	"github.com/innoai-tech/infra/pkg/deploy"
 )
 
 // Command 表示可参与 CLI 命令树组装的对象。
@@ -56,22 +57,32 @@ type CanRuntimeDoc interface {
 	RuntimeDoc(names ...string) ([]string, bool)
 }
 
-func addConfigurator(c *C, flags *pflag.FlagSet, target any, name string, appName string) {
+func addConfigurator(c *C, flags *pflag.FlagSet, target any, name string, appName string) error {
 	envPrefix := c.envPrefix
 	if envPrefix == "" {
 		envPrefix = fmt.Sprintf("%s_", appName)
 	}
 
-	collectFlagsFromConfigurator(c, flags, reflect.ValueOf(target), name, envPrefix, "")
+	start := len(c.flagVars)
+
+	if err := collectFlagsFromConfigurator(c, flags, reflect.ValueOf(target), name, envPrefix, ""); err != nil {
+		return err
+	}
+
+	for _, f := range c.flagVars[start:] {
+		f.Configurator = target
+	}
+
+	return nil
 }
 
-func collectFlagsFromConfigurator(c *C, flags *pflag.FlagSet, rv reflect.Value, prefix string, envPrefix string, parentDoc string) {
+func collectFlagsFromConfigurator(c *C, flags *pflag.FlagSet, rv reflect.Value, prefix string, envPrefix string, parentDoc string) error {
 	for rv.Kind() == reflect.Pointer {
 		rv = rv.Elem()
 	}
 
 	if rv.Kind() != reflect.Struct {
-		return
+		return nil
 	}
 
 	var docer CanRuntimeDoc
@@ -143,10 +154,26 @@ func collectFlagsFromConfigurator(c *C, flags *pflag.FlagSet, rv reflect.Value,
 			flagVar.Expose = tt.Get("expose")
 			flagVar.Secret = tt.Has("secret")
 			flagVar.Volume = tt.Has("volume")
+			flagVar.VolumeType = tt.Get("volume")
+			flagVar.Reloadable = tt.Has("reload")
+
+			switch flagVar.VolumeType {
+			case "", deploy.VolumeSecret, deploy.VolumeConfigMap:
+			default:
+				return fmt.Errorf("%s.%s: unsupported volume type %s", st.Name(), ft.Name, flagVar.VolumeType)
+			}
 
 			if alias, ok := ft.Tag.Lookup("alias"); ok {
 				flagVar.Alias = alias
 			}
+
+			if validate, ok := ft.Tag.Lookup("validate"); ok {
+				rules, err := internal.ParseRules(validate)
+				if err != nil {
+					return fmt.Errorf("%s.%s: %w", st.Name(), ft.Name, err)
+				}
+				flagVar.Rules = rules
+			}
 		}
 
 		if prefix != "" {
@@ -170,17 +197,21 @@ func collectFlagsFromConfigurator(c *C, flags *pflag.FlagSet, rv reflect.Value,
 			mr := flagVar.Value.MapRange()
 
 			for mr.Next() {
-				collectFlagsFromConfigurator(c, flags, mr.Value(), flagName+"_"+mr.Key().String(), envPrefix, doc)
+				if err := collectFlagsFromConfigurator(c, flags, mr.Value(), flagName+"_"+mr.Key().String(), envPrefix, doc); err != nil {
+					return err
+				}
 			}
 
 			continue
 		}
 
 		if ft.Type.Kind() == reflect.Struct && flagVar.Type() != "string" {
+			structPrefix := flagName
 			if ft.Anonymous {
-				collectFlagsFromConfigurator(c, flags, fv, prefix, envPrefix, doc)
-			} else {
-				collectFlagsFromConfigurator(c, flags, fv, flagName, envPrefix, doc)
+				structPrefix = prefix
+			}
+			if err := collectFlagsFromConfigurator(c, flags, fv, structPrefix, envPrefix, doc); err != nil {
+				return err
 			}
 			continue
 		}
@@ -197,4 +228,6 @@ func collectFlagsFromConfigurator(c *C, flags *pflag.FlagSet, rv reflect.Value,
 
 		flagVar.Apply(flags)
 	}
+
+	return nil
 }
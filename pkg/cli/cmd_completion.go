@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+)
+
+// addBuiltinCommands 为根命令添加 completion、gen-docs 与 probe 子命令。
+func (a *app) addBuiltinCommands(root *cobra.Command) {
+	// 使用显式的 completion 子命令替代 cobra 默认命令
+	root.CompletionOptions.DisableDefaultCmd = true
+
+	for _, cmd := range []*cobra.Command{newCompletionCommand(), a.newGenDocsCommand(), newProbeCommand()} {
+		addBuiltinCommand(root, cmd)
+	}
+}
+
+// addBuiltinCommand 添加内置子命令，与应用自身子命令同名时跳过。
+func addBuiltinCommand(root *cobra.Command, cmd *cobra.Command) {
+	if slices.ContainsFunc(root.Commands(), func(c *cobra.Command) bool {
+		return c.Name() == cmd.Name()
+	}) {
+		return
+	}
+	root.AddCommand(cmd)
+}
+
+func newCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:                   "completion [bash|zsh|fish|powershell]",
+		Short:                 "生成 shell 自动补全脚本",
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+
+			return fmt.Errorf("unsupported shell %s", args[0])
+		},
+	}
+}
+
+// registerFlagCompletion 为枚举值提供候选补全，为 volume flag 提供文件路径补全。
+func registerFlagCompletion(cmd *cobra.Command, f *internal.FlagVar) {
+	if f.Volume {
+		_ = cmd.MarkFlagFilename(f.Name)
+		return
+	}
+
+	if len(f.EnumValues) > 0 {
+		values := make([]string, 0, len(f.EnumValues))
+		for _, v := range f.EnumValues {
+			values = append(values, fmt.Sprintf("%v", v))
+		}
+
+		_ = cmd.RegisterFlagCompletionFunc(f.Name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/innoai-tech/infra/pkg/deploy"
+)
+
+// newDeployBundleCommand 返回 deploy-bundle 子命令。
+//
+// 部署包由命令树中各组件的部署规格构造，与本次导出的预设一致；组件间的环境变量引用由 WithDeployBundle 注册的函数连接。
+func (a *app) newDeployBundleCommand() *cobra.Command {
+	output := "./deploy"
+	formats := make([]string, 0)
+
+	cmd := &cobra.Command{
+		Use:   "deploy-bundle",
+		Short: "一次性导出全部组件的部署预设，并以全部组件构造部署包、校验组件间的环境变量引用",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := deploy.NewBundle(a.a.Name)
+			if err != nil {
+				return err
+			}
+
+			for _, c := range a.components() {
+				if err := c.dumpDeployPreset(cmd.Context(), output); err != nil {
+					return err
+				}
+
+				container, err := c.deployContainer()
+				if err != nil {
+					return err
+				}
+
+				if err := b.Add(container); err != nil {
+					return err
+				}
+			}
+
+			if a.deployBundle != nil {
+				if err := a.deployBundle(b); err != nil {
+					return err
+				}
+			}
+
+			if err := b.Validate(); err != nil {
+				return err
+			}
+
+			for _, format := range formats {
+				out := &bytes.Buffer{}
+				filename := ""
+
+				switch strings.TrimSpace(format) {
+				case "kubernetes":
+					filename = b.Name + ".k8s.yaml"
+					err = b.RenderKubernetes(out)
+				case "compose":
+					filename = "compose.yaml"
+					err = b.RenderCompose(out)
+				default:
+					return fmt.Errorf("unsupported deploy bundle format %q, should be one of kubernetes, compose", format)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				if err := os.WriteFile(path.Join(output, filename), out.Bytes(), 0o600); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", output, "部署预设输出目录")
+	cmd.Flags().StringSliceVarP(&formats, "format", "", nil, "同时导出整个应用的部署文件 (ALLOW VALUES: kubernetes, compose)")
+
+	_ = cmd.MarkFlagDirname("output")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"kubernetes", "compose"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// components 返回命令树中声明了 component 的命令，按组件名排序。
+func (a *app) components() []*C {
+	components := make([]*C, 0)
+
+	for _, c := range a.commands {
+		if c.info.Component != nil && !slices.Contains(components, c) {
+			components = append(components, c)
+		}
+	}
+
+	slices.SortFunc(components, func(x, y *C) int {
+		return cmp.Compare(x.info.Component.Name, y.info.Component.Name)
+	})
+
+	return components
+}
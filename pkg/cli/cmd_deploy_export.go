@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/octohelm/gengo/pkg/camelcase"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+	"github.com/innoai-tech/infra/pkg/deploy"
+)
+
+// deployContainer 返回与 --deploy-preset 生成代码等价的 deploy.Container。
+func (c *C) deployContainer() (*deploy.Container, error) {
+	container := &deploy.Container{
+		Name:      camelcase.LowerKebabCase(c.info.Component.Name),
+		Kind:      c.info.Component.Options.Get("kind"),
+		ImageName: fmt.Sprintf("%s/%s", c.info.App.ImageNamespace, c.info.App.Name),
+		Version:   c.info.App.Version,
+		Args:      c.cmdPath,
+		Ports:     map[string]deploy.Port{},
+		Env:       map[string]deploy.EnvVar{},
+	}
+
+	if appName := c.info.App.Name; appName != "" {
+		container.Command = []string{appName}
+	}
+
+	volumes, mounts := flagVolumes(container.Name, c.flagVars)
+	if len(volumes) > 0 {
+		container.Volumes = volumes
+		container.Mounts = mounts
+	}
+
+	container.Resources = componentResources(c.info.Component.Options)
+	container.SecurityContext = componentSecurityContext(c.info.Component.Options)
+
+	for _, f := range c.flagVars {
+		if f.Expose != "" {
+			port, err := strconv.Atoi(exposePort(f))
+			if err != nil {
+				return nil, fmt.Errorf("invalid expose port of ${%s}: %w", f.EnvVar, err)
+			}
+
+			container.Ports[f.Expose] = deploy.Port{
+				Port:              port,
+				Protocol:          "TCP",
+				Endpoint:          "/",
+				ReadinessEndpoint: "/",
+				LivenessEndpoint:  "/",
+			}
+			container.Env[f.EnvVar] = deploy.EnvVar{ValueRef: exposeValueRef(f)}
+			continue
+		}
+
+		value := f.ExportValue()
+		if f.Required {
+			value = ""
+		}
+
+		container.Env[f.EnvVar] = deploy.EnvVar{Value: value, Secret: f.Secret}
+	}
+
+	// 以自身的 probe 子命令检查就绪，不依赖镜像内的 wget 或 curl
+	if u, ok := container.ReadinessURL(); ok && len(container.Command) > 0 {
+		container.Healthcheck = []string{container.Command[0], "probe", u}
+	}
+
+	return container, nil
+}
+
+// flagVolumes 为带 volume 标记的 flag 生成卷及挂载。
+//
+// 未指定卷类型时以 emptyDir 挂载到 flag 的默认值；指定 secret 或 configMap 时 flag 为文件路径，
+// 以名为 "<容器名>-<flag 名>" 的卷只读挂载其所在目录，同一目录只挂载一次。
+func flagVolumes(containerName string, flagVars []*internal.FlagVar) (map[string]deploy.Volume, []deploy.Mount) {
+	volumes := map[string]deploy.Volume{}
+	mounts := make([]deploy.Mount, 0)
+
+	for _, f := range flagVars {
+		if !f.Volume {
+			continue
+		}
+
+		mountPath := f.ExportValue()
+		if mountPath == "" {
+			continue
+		}
+
+		name := camelcase.LowerKebabCase(f.Name)
+
+		if f.VolumeType == "" {
+			volumes[name] = deploy.Volume{Type: deploy.VolumeEmptyDir}
+			mounts = append(mounts, deploy.Mount{Volume: name, MountPath: mountPath})
+			continue
+		}
+
+		mountPath = path.Dir(mountPath)
+
+		if slices.ContainsFunc(mounts, func(m deploy.Mount) bool { return m.MountPath == mountPath }) {
+			continue
+		}
+
+		volumes[name] = deploy.Volume{Type: f.VolumeType, Source: containerName + "-" + name}
+		mounts = append(mounts, deploy.Mount{Volume: name, MountPath: mountPath, ReadOnly: true})
+	}
+
+	return volumes, mounts
+}
+
+// componentResources 解析组件 tag 的 cpu= 与 memory= 选项，取值为 "请求" 或 "请求/限制"。
+func componentResources(options url.Values) *deploy.Resources {
+	cpu, memory := options.Get("cpu"), options.Get("memory")
+	if cpu == "" && memory == "" {
+		return nil
+	}
+
+	r := &deploy.Resources{}
+	r.Requests.CPU, r.Limits.CPU, _ = strings.Cut(cpu, "/")
+	r.Requests.Memory, r.Limits.Memory, _ = strings.Cut(memory, "/")
+	return r
+}
+
+// componentSecurityContext 解析组件 tag 的 readonly 选项。
+func componentSecurityContext(options url.Values) *deploy.SecurityContext {
+	if !options.Has("readonly") {
+		return nil
+	}
+	return &deploy.SecurityContext{ReadOnlyRootFilesystem: true}
+}
+
+// dumpDeployExport 将组件导出为 compose 或 systemd 部署文件，与 Go 预设写入同一目录。
+func (c *C) dumpDeployExport(dest string, formats []string) error {
+	if c.info.Component == nil {
+		return nil
+	}
+
+	container, err := c.deployContainer()
+	if err != nil {
+		return err
+	}
+
+	dest = path.Join(dest, camelcase.LowerSnakeCase(c.info.Component.Name))
+
+	if err := os.MkdirAll(dest, os.ModePerm); err != nil {
+		return err
+	}
+
+	files := map[string]*bytes.Buffer{}
+
+	for _, format := range formats {
+		switch strings.TrimSpace(format) {
+		case "compose":
+			b := &bytes.Buffer{}
+			if err := deploy.RenderCompose(b, container); err != nil {
+				return err
+			}
+			files["compose.yaml"] = b
+		case "systemd":
+			unit := &bytes.Buffer{}
+			if err := deploy.RenderSystemdUnit(unit, container, ""); err != nil {
+				return err
+			}
+			files[container.Name+".service"] = unit
+
+			env := &bytes.Buffer{}
+			if err := deploy.RenderEnvFile(env, container); err != nil {
+				return err
+			}
+			files[container.Name+".env"] = env
+		default:
+			return fmt.Errorf("unsupported deploy export format %q, should be one of compose, systemd", format)
+		}
+	}
+
+	for name, b := range files {
+		if err := os.WriteFile(path.Join(dest, name), b.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,35 +4,74 @@ import (
 	"context"
 	"fmt"
 	"go/format"
+	"maps"
 	"os"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/octohelm/gengo/pkg/camelcase"
 
 	"github.com/innoai-tech/infra/pkg/cli/internal"
+	"github.com/innoai-tech/infra/pkg/deploy"
 )
 
-// dumpDeployPreset 导出组件的 deploy.Container 预设 Go 代码。
+// dumpDeployPreset 导出组件的 deploy.Container 预设 Go 代码，内容与 deployContainer 构造的部署规格一致。
 func (c *C) dumpDeployPreset(ctx context.Context, dest string) error {
 	if c.info.Component == nil {
 		return nil
 	}
 
+	container, err := c.deployContainer()
+	if err != nil {
+		return err
+	}
+
 	componentName := camelcase.LowerSnakeCase(c.info.Component.Name)
 	dest = path.Join(dest, componentName)
 
-	toComment := func(s string) string {
-		if s == "" {
-			return ""
-		}
-		return "// " + strings.ReplaceAll(s, "\n", "\n// ")
+	if err := os.MkdirAll(dest, os.ModePerm); err != nil {
+		return err
+	}
+
+	out := deployPresetSource(componentName, container, c.flagVars)
+
+	// 用 gofmt 格式化生成的代码
+	fmted, err := format.Source([]byte(out))
+	if err != nil {
+		// 格式化失败时仍写入原始内容，方便排查
+		_ = os.WriteFile(path.Join(dest, "container.go"), []byte("// gofmt 格式化失败: "+err.Error()+"\n"+out), 0o600)
+		return err
 	}
 
+	return os.WriteFile(path.Join(dest, "container.go"), fmted, 0o600)
+}
+
+// deployPresetSource 将部署规格写为返回该规格的 Go 代码，flag 的文档、可选与校验规则写为对应端口与环境变量的注释。
+func deployPresetSource(pkgName string, container *deploy.Container, flagVars []*internal.FlagVar) string {
 	w := &gofmtBuilder{}
 
-	w.line("package %s", componentName)
+	doc := func(f *internal.FlagVar) {
+		for line := range strings.Lines(f.Desc) {
+			w.line("// %s", strings.TrimSuffix(line, "\n"))
+		}
+	}
+
+	stringSlice := func(name string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		w.line("%s: []string{", name)
+		w.depth++
+		for _, v := range values {
+			w.line("%q,", v)
+		}
+		w.depth--
+		w.line("},")
+	}
+
+	w.line("package %s", pkgName)
 	w.b.WriteByte('\n')
 	w.line("import %q", "github.com/innoai-tech/infra/pkg/deploy")
 	w.b.WriteByte('\n')
@@ -44,57 +83,46 @@ func (c *C) dumpDeployPreset(ctx context.Context, dest string) error {
 	w.line("c := &deploy.Container{")
 	w.depth++
 
-	imageName := fmt.Sprintf("%s/%s", c.info.App.ImageNamespace, c.info.App.Name)
-	w.line("ImageName: %q,", imageName)
-	w.line("Version: %q,", c.info.App.Version)
-
-	if appName := c.info.App.Name; appName != "" {
-		w.line("Command: []string{%q},", appName)
-	}
-
-	if len(c.cmdPath) > 0 {
-		w.line("Args: []string{")
-		w.depth++
-		for _, n := range c.cmdPath {
-			w.line("%q,", n)
-		}
-		w.depth--
-		w.line("},")
+	w.line("Name: %q,", container.Name)
+	if container.Kind != "" {
+		w.line("Kind: %q,", container.Kind)
 	}
+	w.line("ImageName: %q,", container.ImageName)
+	w.line("Version: %q,", container.Version)
 
-	var flagExposes []*internal.FlagVar
-	var flagEnvs []*internal.FlagVar
-
-	for _, f := range c.flagVars {
-		if f.Expose != "" {
-			flagExposes = append(flagExposes, f)
-			continue
-		}
-		flagEnvs = append(flagEnvs, f)
+	if len(container.Command) == 1 {
+		w.line("Command: []string{%q},", container.Command[0])
+	} else {
+		stringSlice("Command", container.Command)
 	}
+	stringSlice("Args", container.Args)
 
-	if len(flagExposes) > 0 {
+	if len(container.Ports) > 0 {
 		w.line("Ports: map[string]deploy.Port{")
 		w.depth++
 
-		for _, f := range flagExposes {
-			portName := f.Expose
-			parts := strings.Split(f.String(), ":")
-			port := parts[len(parts)-1]
+		for _, name := range slices.Sorted(maps.Keys(container.Ports)) {
+			p := container.Ports[name]
 
-			if f.Desc != "" {
-				for line := range strings.Lines(toComment(f.Desc)) {
-					w.line("%s", line)
-				}
+			if i := slices.IndexFunc(flagVars, func(f *internal.FlagVar) bool { return f.Expose == name }); i >= 0 {
+				doc(flagVars[i])
 			}
 
-			w.line("%q: {", portName)
+			w.line("%q: {", name)
 			w.depth++
-			w.line("Port: %s,", port)
-			w.line("Protocol: %q,", "TCP")
-			w.line("Endpoint: %q,", "/")
-			w.line("ReadinessEndpoint: %q,", "/")
-			w.line("LivenessEndpoint: %q,", "/")
+			w.line("Port: %d,", p.Port)
+			if p.Protocol != "" {
+				w.line("Protocol: %q,", p.Protocol)
+			}
+			if p.Endpoint != "" {
+				w.line("Endpoint: %q,", p.Endpoint)
+			}
+			if p.ReadinessEndpoint != "" {
+				w.line("ReadinessEndpoint: %q,", p.ReadinessEndpoint)
+			}
+			if p.LivenessEndpoint != "" {
+				w.line("LivenessEndpoint: %q,", p.LivenessEndpoint)
+			}
 			w.depth--
 			w.line("},")
 		}
@@ -103,59 +131,117 @@ func (c *C) dumpDeployPreset(ctx context.Context, dest string) error {
 		w.line("},")
 	}
 
-	if len(flagEnvs) > 0 || len(flagExposes) > 0 {
+	if len(container.Env) > 0 {
 		w.line("Env: map[string]deploy.EnvVar{")
 		w.depth++
 
-		for _, f := range flagEnvs {
-			if f.Desc != "" {
-				for line := range strings.Lines(toComment(f.Desc)) {
-					w.line("%s", line)
+		// 先输出普通 flag，再输出 expose flag，其余按变量名排序
+		names := make([]string, 0, len(container.Env))
+		for _, exposed := range []bool{false, true} {
+			for _, f := range flagVars {
+				if _, ok := container.Env[f.EnvVar]; ok && (f.Expose != "") == exposed {
+					names = append(names, f.EnvVar)
 				}
 			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(container.Env)) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
 
-			value := f.DefaultValue()
-			if f.Required {
-				value = ""
-			} else {
-				w.line("%s", "// +optional")
+		for _, name := range names {
+			env := container.Env[name]
+
+			if i := slices.IndexFunc(flagVars, func(f *internal.FlagVar) bool { return f.EnvVar == name }); i >= 0 {
+				f := flagVars[i]
+
+				doc(f)
+				if !f.Required && f.Expose == "" {
+					w.line("%s", "// +optional")
+				}
+				if len(f.Rules) > 0 {
+					w.line("// +validate %s", f.Rules)
+				}
 			}
 
-			w.line("%q: {", f.EnvVar)
+			w.line("%q: {", name)
 			w.depth++
-			w.line("Value: %q,", value)
+			if env.ValueRef != "" {
+				if strings.Contains(env.ValueRef, "`") {
+					w.line("ValueRef: %q,", env.ValueRef)
+				} else {
+					w.line("ValueRef: `%s`,", env.ValueRef)
+				}
+			} else {
+				w.line("Value: %q,", env.Value)
+			}
+			if env.Secret {
+				w.line("Secret: true,")
+			}
 			w.depth--
 			w.line("},")
 		}
 
-		for _, f := range flagExposes {
-			portName := f.Expose
-			parts := strings.Split(f.String(), ":")
-			port := parts[len(parts)-1]
+		w.depth--
+		w.line("},")
+	}
 
-			valueRef := f.String()
-			if port != "" {
-				placeholder := "{{ .Ports[" + strconv.Quote(portName) + "].Port }}"
-				valueRef = strings.Replace(valueRef, port, placeholder, 1)
+	if len(container.Volumes) > 0 {
+		w.line("Volumes: map[string]deploy.Volume{")
+		w.depth++
+		for _, name := range slices.Sorted(maps.Keys(container.Volumes)) {
+			v := container.Volumes[name]
+			if v.Source != "" {
+				w.line("%q: {Type: %q, Source: %q},", name, v.Type, v.Source)
+			} else {
+				w.line("%q: {Type: %q},", name, v.Type)
 			}
+		}
+		w.depth--
+		w.line("},")
+	}
 
-			if f.Desc != "" {
-				for line := range strings.Lines(toComment(f.Desc)) {
-					w.line("%s", line)
-				}
+	if len(container.Mounts) > 0 {
+		w.line("Mounts: []deploy.Mount{")
+		w.depth++
+		for _, m := range container.Mounts {
+			w.indent()
+			fmt.Fprintf(&w.b, "{Volume: %q, MountPath: %q", m.Volume, m.MountPath)
+			if m.SubPath != "" {
+				fmt.Fprintf(&w.b, ", SubPath: %q", m.SubPath)
 			}
-
-			w.line("%q: {", f.EnvVar)
-			w.depth++
-			w.line("ValueRef: `%s`,", valueRef)
-			w.depth--
-			w.line("},")
+			if m.ReadOnly {
+				w.b.WriteString(", ReadOnly: true")
+			}
+			w.b.WriteString("},\n")
 		}
+		w.depth--
+		w.line("},")
+	}
+
+	if r := container.Resources; r != nil {
+		w.line("Resources: &deploy.Resources{")
+		w.depth++
+		w.line("Requests: deploy.ResourceList{CPU: %q, Memory: %q},", r.Requests.CPU, r.Requests.Memory)
+		w.line("Limits: deploy.ResourceList{CPU: %q, Memory: %q},", r.Limits.CPU, r.Limits.Memory)
+		w.depth--
+		w.line("},")
+	}
 
+	if sc := container.SecurityContext; sc != nil {
+		w.line("SecurityContext: &deploy.SecurityContext{")
+		w.depth++
+		w.line("ReadOnlyRootFilesystem: %v,", sc.ReadOnlyRootFilesystem)
+		if sc.RunAsNonRoot {
+			w.line("RunAsNonRoot: true,")
+		}
 		w.depth--
 		w.line("},")
 	}
 
+	stringSlice("Healthcheck", container.Healthcheck)
+
 	w.depth--
 	w.line("}")
 
@@ -165,21 +251,23 @@ func (c *C) dumpDeployPreset(ctx context.Context, dest string) error {
 	w.depth--
 	w.line("}")
 
-	if err := os.MkdirAll(dest, os.ModePerm); err != nil {
-		return err
-	}
+	return w.b.String()
+}
 
-	out := w.b.String()
+// exposePort 返回 expose flag 值中的端口号。
+func exposePort(f *internal.FlagVar) string {
+	parts := strings.Split(f.String(), ":")
+	return parts[len(parts)-1]
+}
 
-	// 用 gofmt 格式化生成的代码
-	fmted, err := format.Source([]byte(out))
-	if err != nil {
-		// 格式化失败时仍写入原始内容，方便排查
-		_ = os.WriteFile(path.Join(dest, "container.go"), []byte("// gofmt 格式化失败: "+err.Error()+"\n"+out), 0o600)
-		return err
+// exposeValueRef 将 expose flag 值中的端口号替换为引用 Container.Ports 的模板。
+func exposeValueRef(f *internal.FlagVar) string {
+	valueRef := f.String()
+	if port := exposePort(f); port != "" {
+		placeholder := "{{ .Ports[" + strconv.Quote(f.Expose) + "].Port }}"
+		valueRef = strings.Replace(valueRef, port, placeholder, 1)
 	}
-
-	return os.WriteFile(path.Join(dest, "container.go"), fmted, 0o600)
+	return valueRef
 }
 
 // gofmtBuilder 辅助生成带一致缩进的 Go 代码。
@@ -113,7 +113,7 @@ func (c *C) dumpK8sConfiguration(ctx context.Context, dest string) error {
 		}
 
 		w.line("%s", toComment(f.Desc))
-		w.line("config: %q: string | *%q", f.EnvVar, f.DefaultValue())
+		w.line("config: %q: string | *%q", f.EnvVar, f.ExportValue())
 	}
 
 	if len(flagExposes) > 0 {
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+)
+
+// configSchema 为 JSON Schema（draft 2020-12）的子集，x- 前缀字段承载部署相关标记。
+type configSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type    string `json:"type,omitempty"`
+	Format  string `json:"format,omitempty"`
+	Default any    `json:"default,omitempty"`
+	Enum    []any  `json:"enum,omitempty"`
+
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	WriteOnly bool     `json:"writeOnly,omitempty"`
+
+	Items *configSchema `json:"items,omitempty"`
+
+	Properties           map[string]*configSchema `json:"properties,omitempty"`
+	Required             []string                 `json:"required,omitempty"`
+	AdditionalProperties *bool                    `json:"additionalProperties,omitempty"`
+
+	Flag     string `json:"x-flag,omitempty"`
+	EnvVar   string `json:"x-env-var,omitempty"`
+	Secret   bool   `json:"x-secret,omitempty"`
+	Expose   string `json:"x-expose,omitempty"`
+	Volume   bool   `json:"x-volume,omitempty"`
+	Validate string `json:"x-validate,omitempty"`
+}
+
+func (c *C) dumpConfigSchema(w io.Writer) error {
+	additional := false
+
+	s := &configSchema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		Title:                strings.Join(c.cmdPath, " "),
+		Description:          c.info.Desc,
+		Type:                 "object",
+		Properties:           map[string]*configSchema{},
+		Required:             make([]string, 0),
+		AdditionalProperties: &additional,
+	}
+
+	if c.info.App != nil {
+		s.Title = strings.TrimSpace(c.info.App.Name + " " + s.Title)
+	}
+
+	for _, f := range c.flagVars {
+		s.Properties[f.EnvVar] = flagSchema(f)
+
+		if f.Required {
+			s.Required = append(s.Required, f.EnvVar)
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(s)
+}
+
+func flagSchema(f *internal.FlagVar) *configSchema {
+	t := f.Value.Type()
+
+	s := &configSchema{
+		Description: f.Desc,
+		Flag:        f.Name,
+		EnvVar:      f.EnvVar,
+		Secret:      f.Secret,
+		WriteOnly:   f.Secret,
+		Expose:      f.Expose,
+		Volume:      f.Volume,
+		Validate:    f.Rules.String(),
+	}
+
+	// 值本身的约束，切片时作用于每个元素
+	item := s
+
+	if t.Kind() == reflect.Slice && !isTextType(t) {
+		s.Type = "array"
+		s.Items = &configSchema{}
+		item = s.Items
+		item.Type, item.Format = schemaType(t.Elem())
+	} else {
+		s.Type, s.Format = schemaType(t)
+	}
+
+	if item.Format == goDurationFormat {
+		item.Pattern = goDurationPattern
+	}
+
+	if len(f.EnumValues) > 0 {
+		item.Enum = f.EnumValues
+	}
+
+	for _, r := range f.Rules {
+		applyRule(item, r)
+	}
+
+	switch {
+	case f.Secret:
+		// 敏感值只导出引用
+		if f.SecretRef != "" {
+			s.Default = f.SecretRef
+		}
+	case !f.Required && !f.Value.IsZero():
+		s.Default = schemaValue(f.Value)
+	}
+
+	return s
+}
+
+func applyRule(s *configSchema, r *internal.Rule) {
+	switch r.Name {
+	case "min", "max":
+		n, err := strconv.ParseFloat(r.Arg, 64)
+		if err != nil {
+			return
+		}
+
+		switch s.Type {
+		case "integer", "number":
+			if r.Name == "min" {
+				s.Minimum = &n
+			} else {
+				s.Maximum = &n
+			}
+		case "string":
+			l := int(n)
+			if r.Name == "min" {
+				s.MinLength = &l
+			} else {
+				s.MaxLength = &l
+			}
+		}
+	case "pattern":
+		s.Pattern = r.Arg
+	case "oneof":
+		if len(s.Enum) == 0 {
+			for _, v := range strings.Fields(r.Arg) {
+				s.Enum = append(s.Enum, v)
+			}
+		}
+	case "url":
+		s.Format = "uri"
+	}
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// JSON Schema 的 duration 格式为 ISO 8601，time.Duration 使用自定义格式并以 pattern 约束 Go 的写法，如 1m30s。
+const (
+	goDurationFormat  = "x-go-duration"
+	goDurationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h))+)$`
+)
+
+func isTextType(t reflect.Type) bool {
+	return t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler)
+}
+
+var textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+func schemaType(t reflect.Type) (string, string) {
+	if t == durationType {
+		return "string", goDurationFormat
+	}
+
+	if isTextType(t) {
+		return "string", ""
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean", ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", ""
+	case reflect.Float32, reflect.Float64:
+		return "number", ""
+	default:
+		return "string", ""
+	}
+}
+
+func schemaValue(v reflect.Value) any {
+	if v.Kind() == reflect.Slice && !isTextType(v.Type()) {
+		list := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, schemaValue(v.Index(i)))
+		}
+		return list
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch typ, _ := schemaType(v.Type()); typ {
+	case "boolean":
+		return reflect.Indirect(v).Bool()
+	case "integer", "number":
+		// 由 json 按数值编码
+		return reflect.Indirect(v).Interface()
+	}
+
+	return (&internal.FlagVar{Value: v}).DefaultValue()
+}
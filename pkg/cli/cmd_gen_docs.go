@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+)
+
+func (a *app) newGenDocsCommand() *cobra.Command {
+	output := "./docs"
+
+	cmd := &cobra.Command{
+		Use:   "gen-docs",
+		Short: "生成 man 手册与 Markdown 参考文档",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.genDocs(cmd.Root(), output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", output, "文档输出目录，man 手册写入其下的 man1 子目录")
+	_ = cmd.MarkFlagDirname("output")
+
+	return cmd
+}
+
+// docFlag 汇总文档中单个 flag 的展示信息。
+type docFlag struct {
+	name       string
+	shorthand  string
+	envVar     string
+	defaultVal string
+	desc       string
+	enumValues []string
+	rules      string
+	required   bool
+}
+
+func (a *app) genDocs(root *cobra.Command, output string) error {
+	manDir := filepath.Join(output, "man1")
+
+	if err := os.MkdirAll(manDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var walk func(cmd *cobra.Command) error
+
+	walk = func(cmd *cobra.Command) error {
+		if cmd.Hidden || cmd.Name() == "help" {
+			return nil
+		}
+
+		basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_")
+		flags := a.docFlags(cmd)
+
+		if err := os.WriteFile(filepath.Join(output, basename+".md"), []byte(markdownDoc(cmd, flags)), 0o600); err != nil {
+			return err
+		}
+
+		manName := strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+		if err := os.WriteFile(filepath.Join(manDir, manName+".1"), []byte(manDoc(cmd, flags, a.version)), 0o600); err != nil {
+			return err
+		}
+
+		for _, sub := range cmd.Commands() {
+			if err := walk(sub); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(root)
+}
+
+func (a *app) docFlags(cmd *cobra.Command) []docFlag {
+	flagVars := map[string]*internal.FlagVar{}
+
+	if c, ok := a.commands[cmd]; ok {
+		for _, f := range c.flagVars {
+			flagVars[f.Name] = f
+		}
+	}
+
+	flags := make([]docFlag, 0)
+
+	cmd.NonInheritedFlags().VisitAll(func(pf *pflag.Flag) {
+		if pf.Hidden {
+			return
+		}
+
+		d := docFlag{
+			name:       pf.Name,
+			shorthand:  pf.Shorthand,
+			defaultVal: pf.DefValue,
+			desc:       pf.Usage,
+		}
+
+		if f, ok := flagVars[pf.Name]; ok {
+			d.envVar = f.EnvVar
+			d.defaultVal = f.ExportValue()
+			d.desc = f.Desc
+			d.rules = f.Rules.String()
+			d.required = f.Required
+
+			for _, v := range f.EnumValues {
+				d.enumValues = append(d.enumValues, fmt.Sprintf("%v", v))
+			}
+		}
+
+		flags = append(flags, d)
+	})
+
+	return flags
+}
+
+func markdownDoc(cmd *cobra.Command, flags []docFlag) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "# %s\n\n", cmd.CommandPath())
+
+	if cmd.Short != "" {
+		fmt.Fprintf(b, "%s\n\n", cmd.Short)
+	}
+
+	fmt.Fprintf(b, "```\n%s\n```\n\n", cmd.UseLine())
+
+	if len(flags) > 0 {
+		b.WriteString("## Flags\n\n")
+		b.WriteString("| Flag | Env | Default | Description |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+
+		for _, f := range flags {
+			name := "`--" + f.name + "`"
+			if f.shorthand != "" {
+				name = "`-" + f.shorthand + "`, " + name
+			}
+
+			env := ""
+			if f.envVar != "" {
+				env = "`" + f.envVar + "`"
+			}
+
+			def := ""
+			if f.defaultVal != "" {
+				def = "`" + f.defaultVal + "`"
+			}
+
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", name, env, def, markdownCell(docFlagDesc(f)))
+		}
+
+		b.WriteString("\n")
+	}
+
+	if subs := visibleCommands(cmd); len(subs) > 0 {
+		b.WriteString("## Commands\n\n")
+
+		for _, sub := range subs {
+			fmt.Fprintf(b, "- [%s](%s.md) %s\n", sub.CommandPath(), strings.ReplaceAll(sub.CommandPath(), " ", "_"), sub.Short)
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func manDoc(cmd *cobra.Command, flags []docFlag, version string) string {
+	b := &strings.Builder{}
+
+	title := strings.ToUpper(strings.ReplaceAll(cmd.CommandPath(), " ", "-"))
+
+	fmt.Fprintf(b, ".TH %q \"1\" \"\" %q \"\"\n", title, cmd.Root().Name()+" "+version)
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(b, "%s \\- %s\n", roffEscape(strings.ReplaceAll(cmd.CommandPath(), " ", "-")), roffEscape(cmd.Short))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(b, "\\fB%s\\fP\n", roffEscape(cmd.UseLine()))
+
+	if len(flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+
+		for _, f := range flags {
+			b.WriteString(".TP\n")
+
+			name := "\\fB\\-\\-" + roffEscape(f.name) + "\\fP"
+			if f.shorthand != "" {
+				name = "\\fB\\-" + roffEscape(f.shorthand) + "\\fP, " + name
+			}
+			if f.defaultVal != "" {
+				name += "=" + roffEscape(f.defaultVal)
+			}
+			fmt.Fprintf(b, "%s\n", name)
+
+			for line := range strings.Lines(docFlagDesc(f)) {
+				fmt.Fprintf(b, "%s\n.br\n", roffEscape(strings.TrimRight(line, "\n")))
+			}
+
+			if f.envVar != "" {
+				fmt.Fprintf(b, "Env: \\fI%s\\fP\n", roffEscape(f.envVar))
+			}
+		}
+	}
+
+	if subs := visibleCommands(cmd); len(subs) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+
+		names := make([]string, 0, len(subs))
+		for _, sub := range subs {
+			names = append(names, "\\fB"+roffEscape(strings.ReplaceAll(sub.CommandPath(), " ", "-"))+"\\fP(1)")
+		}
+		fmt.Fprintf(b, "%s\n", strings.Join(names, ", "))
+	}
+
+	return b.String()
+}
+
+func docFlagDesc(f docFlag) string {
+	desc := f.desc
+
+	if f.required {
+		desc += "\n必填"
+	}
+	if len(f.enumValues) > 0 {
+		desc += "\n可选值: " + strings.Join(f.enumValues, ", ")
+	}
+	if f.rules != "" {
+		desc += "\n校验: " + f.rules
+	}
+
+	return strings.TrimPrefix(desc, "\n")
+}
+
+func visibleCommands(cmd *cobra.Command) []*cobra.Command {
+	subs := make([]*cobra.Command, 0)
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden || sub.Name() == "help" {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newProbeCommand 返回 probe 子命令，供导出的 compose healthcheck 与 systemd 就绪等待调用，镜像内无需 wget 或 curl。
+func newProbeCommand() *cobra.Command {
+	timeout := time.Second
+
+	cmd := &cobra.Command{
+		Use:   "probe <url>",
+		Short: "请求 HTTP 地址检查服务是否就绪，响应状态码不低于 400 时以非零状态退出",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return probe(cmd.Context(), args[0], timeout)
+		},
+	}
+
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", timeout, "请求超时时间")
+
+	return cmd
+}
+
+func probe(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("probe %s failed: %s", url, resp.Status)
+	}
+
+	return nil
+}
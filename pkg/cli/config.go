@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+)
+
+// loadConfigSources 按优先级加载配置来源：环境变量（含 env 文件）优先于配置文件。
+func loadConfigSources(envFile string, configFile string, flagVars []*internal.FlagVar) ([]internal.ConfigSource, error) {
+	envVars, err := internal.LoadEnvVars(envFile, os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []internal.ConfigSource{envVars}
+
+	if configFile != "" {
+		c, err := internal.LoadConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.Validate(flagVars); err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, c)
+	}
+
+	return sources, nil
+}
@@ -2,8 +2,14 @@
 //
 // 它负责：
 //   - 从命令 struct 收集 args、flags、env 绑定和运行时文档
+//   - 按 flag > 环境变量 > `--config-file` 配置文件 > 默认值的优先级绑定配置，并记录每个值的来源
+//   - 按 `validate` tag（min/max/pattern/oneof/url/duration）校验配置，一次性报告所有违规项
+//   - 通过 `SecretResolver` 注册表解析 secret 字段中的 `file://`、`env:` 等引用，展示与导出时只保留引用
 //   - 将命令树与 configuration 生命周期拼接为可执行入口
-//   - 提供 `dump-k8s`、配置展示等命令层辅助能力
+//   - 提供 `dump-k8s`、配置展示等命令层辅助能力，`--deploy-export=compose|systemd` 随部署预设导出 compose 与 systemd 部署文件，`deploy-bundle` 一次导出全部组件的预设，并以全部组件构造部署包，经 `WithDeployBundle` 注册的函数连接组件后校验组件间引用；导出的 healthcheck 调用自身的 `probe` 子命令
+//   - 通过 `--dump-config-schema` 导出 JSON Schema，描述每个配置项的环境变量名、类型、默认值、必填、secret、枚举、expose、volume 与文档
+//   - 提供 `completion` 子命令生成 shell 补全脚本（枚举 flag 补全候选值、volume flag 补全路径），`gen-docs` 生成 man 手册与 Markdown 参考
+//   - 重载时重新读取环境变量、`--env-file` 与 `--config-file`，将变更应用到实现 `configuration.Reloader` 的配置对象中标记了 `reload` 的字段
 //
 // 它不负责：
 //   - 定义业务 API 契约和领域逻辑
@@ -1,6 +1,11 @@
 package internal
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -15,6 +20,88 @@ func EnvVarsFromEnviron(environ []string) EnvVars {
 	return envVars
 }
 
+// LoadEnvVars 读取 env 文件并叠加 environ，environ 中的同名变量优先；envFile 为空时只使用 environ。
+func LoadEnvVars(envFile string, environ []string) (EnvVars, error) {
+	envVars := EnvVars{}
+
+	if envFile != "" {
+		data, err := os.ReadFile(envFile)
+		if err != nil {
+			return nil, err
+		}
+
+		fromFile, err := ParseDotEnv(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse env file %s failed: %w", envFile, err)
+		}
+
+		for k, v := range fromFile {
+			envVars[k] = v
+		}
+	}
+
+	for k, v := range EnvVarsFromEnviron(environ) {
+		envVars[k] = v
+	}
+
+	return envVars, nil
+}
+
+// ParseDotEnv 解析 dotenv 格式内容。
+//
+// 支持 `#` 注释、空行、`export` 前缀以及单双引号包裹的值。
+func ParseDotEnv(data []byte) (EnvVars, error) {
+	envVars := EnvVars{}
+
+	if err := parseDotEnv(data, envVars.Add); err != nil {
+		return nil, err
+	}
+
+	return envVars, nil
+}
+
+func parseDotEnv(data []byte, add func(key, value string)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: missing '='", n)
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 {
+			switch value[0] {
+			case '"':
+				if value[len(value)-1] == '"' {
+					unquoted, err := strconv.Unquote(value)
+					if err != nil {
+						return fmt.Errorf("line %d: %w", n, err)
+					}
+					value = unquoted
+				}
+			case '\'':
+				if value[len(value)-1] == '\'' {
+					value = value[1 : len(value)-1]
+				}
+			}
+		}
+
+		add(key, value)
+	}
+
+	return scanner.Err()
+}
+
 // EnvVars 表示环境变量的键值映射。
 type EnvVars map[string]string
 
@@ -31,3 +31,22 @@ func TestEnvVars(t *testing.T) {
 		})
 	})
 }
+
+func TestParseDotEnv(t *testing.T) {
+	envVars, err := ParseDotEnv([]byte(`
+# comment
+export APP_A=1
+APP_B = "x y"
+APP_C='z'
+`))
+
+	Then(
+		t, "dotenv 支持注释、export 前缀与引号",
+		Expect(err, Equal(error(nil))),
+		Expect(envVars, Equal(EnvVars{
+			"APPA": "1",
+			"APPB": "x y",
+			"APPC": "z",
+		})),
+	)
+}
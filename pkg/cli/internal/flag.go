@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"cmp"
 	"encoding"
 	"encoding/csv"
 	"fmt"
@@ -29,27 +30,91 @@ type FlagVar struct {
 	Value reflect.Value
 	// EnumValues 允许枚举值
 	EnumValues []any
+	// Rules 校验规则
+	Rules Rules
 
 	// Secret 是否为敏感值
 	Secret bool
+	// SecretRef 敏感值的引用，如 file:///run/secrets/db，展示时用于替代实际值
+	SecretRef string
 	// Expose 端口暴露标识
 	Expose string
 	// Volume 是否为卷挂载
 	Volume bool
+	// VolumeType 卷类型，为空时以 emptyDir 挂载到值本身；secret 或 configMap 用于文件 flag，只读挂载值所在目录
+	VolumeType string
+	// Reloadable 是否支持重载时热更新，需所属配置对象实现 configuration.Reloader
+	Reloadable bool
+
+	// Configurator 所属的配置对象
+	Configurator any
+
+	// Source 当前值的来源
+	Source string
 
 	changed bool
+	// 是否通过命令行设置
+	fromFlag bool
+	// 应用配置来源之前的值，即 flag 与默认值的结果
+	base reflect.Value
 }
 
-// FromEnvVars 从环境变量中设置 flag 的值。
-func (f *FlagVar) FromEnvVars(vars EnvVars) error {
-	if v, ok := vars.Get(f.EnvVar); ok {
-		if err := f.Set(v); err != nil {
-			return fmt.Errorf("set value from %s failed: %w", f.EnvVar, err)
-		}
+// Bind 按来源优先级设置 flag 的值并记录来源。
+//
+// 命令行 flag 优先级最高，其余按 sources 顺序取第一个命中的值；
+// 首次调用时会记录应用配置来源之前的值，供 Resolve 在配置移除后回退。
+func (f *FlagVar) Bind(sources ...ConfigSource) error {
+	if !f.base.IsValid() {
+		f.base = reflect.New(f.Value.Type()).Elem()
+		f.base.Set(f.Value)
 	}
+
+	next, source, err := f.Resolve(sources...)
+	if err != nil {
+		return err
+	}
+
+	f.Value.Set(next)
+	f.Source = source
+
 	return nil
 }
 
+// Resolve 按来源优先级计算 flag 的新值及其来源，不修改当前值。
+func (f *FlagVar) Resolve(sources ...ConfigSource) (reflect.Value, string, error) {
+	next := reflect.New(f.Value.Type()).Elem()
+	if f.base.IsValid() {
+		next.Set(f.base)
+	} else {
+		next.Set(f.Value)
+	}
+
+	if f.fromFlag {
+		return next, SourceFlag, nil
+	}
+
+	for _, source := range sources {
+		if v, ok := source.Lookup(f); ok {
+			resolved := &FlagVar{Value: next, Required: f.Required}
+			if err := resolved.set(v); err != nil {
+				return reflect.Value{}, "", fmt.Errorf("set value of %s from %s failed: %w", f.EnvVar, source.Name(), err)
+			}
+			return next, source.Name(), nil
+		}
+	}
+
+	return next, SourceDefault, nil
+}
+
+// Parse 将字符串解析为与 flag 同类型的新值，不修改当前值。
+func (f *FlagVar) Parse(s string) (reflect.Value, error) {
+	v := reflect.New(f.Value.Type()).Elem()
+	if err := (&FlagVar{Value: v, Required: f.Required}).set(s); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
 // Apply 将 flag 注册到 pflag.FlagSet。
 func (f *FlagVar) Apply(flags *pflag.FlagSet) {
 	ff := flags.VarPF(f, f.Name, f.Alias, f.Usage())
@@ -121,8 +186,13 @@ func (f *FlagVar) typ(t reflect.Type) string {
 	return t.Kind().String()
 }
 
-// Set 从字符串解析并设置 flag 的值。
+// Set 从字符串解析并设置 flag 的值，并标记该值来自命令行。
 func (f *FlagVar) Set(s string) error {
+	f.fromFlag = true
+	return f.set(s)
+}
+
+func (f *FlagVar) set(s string) error {
 	if f.Value.Kind() == reflect.Slice {
 		if s == "" && !f.Required {
 			return nil
@@ -188,6 +258,12 @@ func (f *FlagVar) Usage() string {
 		s.WriteString(")")
 	}
 
+	if len(f.Rules) > 0 {
+		s.WriteString(" (VALIDATE: ")
+		s.WriteString(f.Rules.String())
+		s.WriteString(")")
+	}
+
 	if len(f.EnvVar) > 0 {
 		s.WriteString(" ${")
 		s.WriteString(f.EnvVar)
@@ -197,13 +273,46 @@ func (f *FlagVar) Usage() string {
 	return s.String()
 }
 
-// Info 返回 flag 的环境变量名和当前值。
+// Validate 校验给定值是否满足必填要求与校验规则；非必填的零值表示未设置，跳过全部规则。
+func (f *FlagVar) Validate(v reflect.Value) error {
+	if v.IsZero() {
+		if f.Required {
+			return fmt.Errorf("缺失必填配置 ${%s}, 可通过环境变量、配置文件或 --%s 设置", f.EnvVar, f.Name)
+		}
+		return nil
+	}
+
+	if failed := f.Rules.Validate(v); len(failed) > 0 {
+		value := (&FlagVar{Value: v, Secret: f.Secret}).SecurityString()
+		return fmt.Errorf("配置 ${%s} 的值 %q 不满足 %s", f.EnvVar, value, failed)
+	}
+
+	return nil
+}
+
+// ExportValue 返回导出部署配置时使用的值；敏感值只导出引用，不导出字面值。
+func (f *FlagVar) ExportValue() string {
+	if f.Secret {
+		return f.SecretRef
+	}
+	return f.DefaultValue()
+}
+
+// Info 返回 flag 的环境变量名、当前值及其来源。
 func (f *FlagVar) Info() string {
+	return fmt.Sprintf("%s = %s # %s", f.EnvVar, f.SecurityString(), cmp.Or(f.Source, SourceDefault))
+}
+
+// SecurityString 返回可安全输出的当前值，敏感值会被遮罩；来自引用的敏感值展示引用本身。
+func (f *FlagVar) SecurityString() string {
+	if f.SecretRef != "" {
+		return f.SecretRef
+	}
 	if s, ok := f.Value.Interface().(interface{ SecurityString() string }); ok {
-		return fmt.Sprintf("%s = %s", f.EnvVar, s.SecurityString())
+		return s.SecurityString()
 	}
 	if f.Secret {
-		return fmt.Sprintf("%s = %s", f.EnvVar, strings.Repeat("-", len(f.DefaultValue())))
+		return strings.Repeat("-", len(f.DefaultValue()))
 	}
-	return fmt.Sprintf("%s = %s", f.EnvVar, f.DefaultValue())
+	return f.DefaultValue()
 }
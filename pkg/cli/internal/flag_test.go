@@ -86,3 +86,61 @@ func TestFlagVar(t *testing.T) {
 		})
 	})
 }
+
+func TestFlagVarBind(t *testing.T) {
+	newFlagVar := func() *FlagVar {
+		v := &FlagVar{
+			Name:   "mode",
+			EnvVar: "APP_MODE",
+			Value:  reflect.New(reflect.TypeFor[string]()).Elem(),
+		}
+		v.Value.SetString("default")
+		return v
+	}
+
+	env := EnvVars{"APPMODE": "from-env"}
+	file := &ConfigFile{vars: EnvVars{"MODE": "from-file"}}
+
+	t.Run("GIVEN env and file sources", func(t *testing.T) {
+		v := newFlagVar()
+
+		Must(t, func() error { return v.Bind(env, file) })
+
+		changed := MustValue(t, func() (reflect.Value, error) {
+			next, _, err := v.Resolve(EnvVars{}, file)
+			return next, err
+		})
+
+		Then(
+			t, "env 优先于配置文件，移除 env 后回退到配置文件",
+			Expect(v.Value.String(), Equal("from-env")),
+			Expect(v.Source, Equal(SourceEnv)),
+			Expect(changed.String(), Equal("from-file")),
+		)
+	})
+
+	t.Run("GIVEN a value set by command line", func(t *testing.T) {
+		v := newFlagVar()
+
+		Must(t, func() error { return v.Set("from-flag") })
+		Must(t, func() error { return v.Bind(env, file) })
+
+		Then(
+			t, "命令行 flag 优先级最高",
+			Expect(v.Value.String(), Equal("from-flag")),
+			Expect(v.Source, Equal(SourceFlag)),
+		)
+	})
+
+	t.Run("GIVEN no source", func(t *testing.T) {
+		v := newFlagVar()
+
+		Must(t, func() error { return v.Bind() })
+
+		Then(
+			t, "保留默认值",
+			Expect(v.Value.String(), Equal("default")),
+			Expect(v.Info(), Equal("APP_MODE = default # default")),
+		)
+	})
+}
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SourceDefault 表示值来自默认值。
+	SourceDefault = "default"
+	// SourceFlag 表示值来自命令行 flag。
+	SourceFlag = "flag"
+	// SourceEnv 表示值来自环境变量或 env 文件。
+	SourceEnv = "env"
+	// SourceFile 表示值来自配置文件。
+	SourceFile = "file"
+)
+
+// ConfigSource 表示一层配置来源。
+type ConfigSource interface {
+	// Name 来源名称，用于展示配置值来源
+	Name() string
+	// Lookup 查找 flag 在该来源中的值
+	Lookup(f *FlagVar) (string, bool)
+}
+
+// Name 返回环境变量来源名称。
+func (envVars EnvVars) Name() string {
+	return SourceEnv
+}
+
+// Lookup 按 flag 的环境变量名查找值。
+func (envVars EnvVars) Lookup(f *FlagVar) (string, bool) {
+	return envVars.Get(f.EnvVar)
+}
+
+// ConfigFile 表示从配置文件读取的扁平化配置。
+//
+// 键可以是 flag 的环境变量名，也可以是与 struct 字段层级一致的嵌套路径，
+// 匹配时忽略大小写与分隔符。
+type ConfigFile struct {
+	// Filename 配置文件路径
+	Filename string
+
+	vars EnvVars
+	keys map[string]string
+}
+
+// LoadConfigFile 按扩展名读取 YAML、JSON、TOML 或 dotenv 格式的配置文件。
+func LoadConfigFile(filename string) (*ConfigFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+
+	switch ext := strings.ToLower(filepath.Ext(filename)); {
+	case ext == ".yaml" || ext == ".yml":
+		m := map[string]any{}
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+		if err := flattenConfig(values, "", m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+	case ext == ".json":
+		m := map[string]any{}
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		if err := d.Decode(&m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+		if err := flattenConfig(values, "", m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+	case ext == ".toml":
+		m := map[string]any{}
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+		if err := flattenConfig(values, "", m); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+	case ext == ".env" || strings.HasPrefix(filepath.Base(filename), ".env"):
+		if err := parseDotEnv(data, func(key, value string) {
+			values[key] = value
+		}); err != nil {
+			return nil, fmt.Errorf("parse config file %s failed: %w", filename, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s", filename)
+	}
+
+	c := &ConfigFile{
+		Filename: filename,
+		vars:     EnvVars{},
+		keys:     map[string]string{},
+	}
+
+	for key, value := range values {
+		c.vars.Add(key, value)
+		c.keys[toUpperDigit(key)] = key
+	}
+
+	return c, nil
+}
+
+// Name 返回配置文件来源名称。
+func (c *ConfigFile) Name() string {
+	return SourceFile
+}
+
+// Lookup 按环境变量名或 flag 名查找值。
+func (c *ConfigFile) Lookup(f *FlagVar) (string, bool) {
+	if v, ok := c.vars.Get(f.EnvVar); ok {
+		return v, true
+	}
+	return c.vars.Get(f.Name)
+}
+
+// Validate 检查配置文件中是否存在无法匹配任何 flag 的键。
+func (c *ConfigFile) Validate(flagVars []*FlagVar) error {
+	known := map[string]bool{}
+	for _, f := range flagVars {
+		known[toUpperDigit(f.EnvVar)] = true
+		known[toUpperDigit(f.Name)] = true
+	}
+
+	unknown := make([]string, 0)
+	for k, key := range c.keys {
+		if !known[k] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("unknown keys in config file %s: %s", c.Filename, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+func flattenConfig(values map[string]string, prefix string, v any) error {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, sub := range x {
+			key := k
+			if prefix != "" {
+				key = prefix + "_" + k
+			}
+			if err := flattenConfig(values, key, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		list := make([]string, 0, len(x))
+		for _, item := range x {
+			switch item.(type) {
+			case map[string]any, []any:
+				return fmt.Errorf("%s: only list of scalar values is supported", prefix)
+			}
+			list = append(list, scalarString(item))
+		}
+		values[prefix] = csvString(list)
+		return nil
+	case []map[string]any:
+		// TOML 的表数组
+		return fmt.Errorf("%s: only list of scalar values is supported", prefix)
+	default:
+		if prefix == "" {
+			return fmt.Errorf("config must be an object")
+		}
+		values[prefix] = scalarString(x)
+		return nil
+	}
+}
+
+func scalarString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func csvString(list []string) string {
+	b := &strings.Builder{}
+	w := csv.NewWriter(b)
+	_ = w.Write(list)
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"config.yaml": "server:\n  addr: \":8080\"\nAPP_LOG_LEVEL: debug\ntags: [a, \"b,c\"]\n",
+		"config.json": `{"server":{"addr":":8080"},"APP_LOG_LEVEL":"debug","tags":["a","b,c"]}`,
+		"config.toml": "APP_LOG_LEVEL = \"debug\" # comment\ntags = [\"a\", \"b,c\"]\n\n[server]\naddr = \":8080\"\n",
+		".env":        "APP_LOG_LEVEL=debug\nSERVER_ADDR=:8080\nTAGS=a,\"b,c\"\n",
+	}
+
+	flagVars := []*FlagVar{
+		{Name: "server-addr", EnvVar: "APP_SERVER_ADDR"},
+		{Name: "log-level", EnvVar: "APP_LOG_LEVEL"},
+		{Name: "tags", EnvVar: "APP_TAGS"},
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, name)
+			if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+				t.Fatalf("write config file: %v", err)
+			}
+
+			c := MustValue(t, func() (*ConfigFile, error) {
+				return LoadConfigFile(filename)
+			})
+
+			addr, _ := c.Lookup(flagVars[0])
+			level, _ := c.Lookup(flagVars[1])
+			tags, _ := c.Lookup(flagVars[2])
+
+			Then(
+				t, "嵌套路径与环境变量名都可匹配 flag",
+				Expect(addr, Equal(":8080")),
+				Expect(level, Equal("debug")),
+				Expect(tags, Equal(`a,"b,c"`)),
+				ExpectDo(func() error { return c.Validate(flagVars) }),
+			)
+		})
+	}
+
+	t.Run("toml", func(t *testing.T) {
+		filename := filepath.Join(dir, "full.toml")
+		content := "server = { addr = \":8080\" }\ntags = [\n  \"a\",\n  \"b,c\",\n]\nAPP_LOG_LEVEL = \"\"\"\ndebug\"\"\"\n"
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatalf("write config file: %v", err)
+		}
+
+		c := MustValue(t, func() (*ConfigFile, error) {
+			return LoadConfigFile(filename)
+		})
+
+		addr, _ := c.Lookup(flagVars[0])
+		level, _ := c.Lookup(flagVars[1])
+		tags, _ := c.Lookup(flagVars[2])
+
+		Then(
+			t, "支持内联表、多行数组与多行字符串",
+			Expect(addr, Equal(":8080")),
+			Expect(level, Equal("debug")),
+			Expect(tags, Equal(`a,"b,c"`)),
+		)
+
+		t.Run("table array", func(t *testing.T) {
+			filename := filepath.Join(dir, "table-array.toml")
+			if err := os.WriteFile(filename, []byte("[[servers]]\naddr = \":8080\"\n"), 0o600); err != nil {
+				t.Fatalf("write config file: %v", err)
+			}
+
+			_, err := LoadConfigFile(filename)
+
+			Then(
+				t, "表数组无法映射到 flag，返回错误",
+				Expect(err != nil, Equal(true)),
+			)
+		})
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		filename := filepath.Join(dir, "unknown.yaml")
+		if err := os.WriteFile(filename, []byte("server:\n  port: 80\nother: 1\n"), 0o600); err != nil {
+			t.Fatalf("write config file: %v", err)
+		}
+
+		c := MustValue(t, func() (*ConfigFile, error) {
+			return LoadConfigFile(filename)
+		})
+
+		err := c.Validate(flagVars)
+
+		Then(
+			t, "未知键会作为错误一并报告",
+			Expect(err != nil, Equal(true)),
+			Expect(err.Error(), Equal("unknown keys in config file "+filename+": other, server_port")),
+		)
+	})
+}
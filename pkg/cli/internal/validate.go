@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	encodingx "github.com/octohelm/x/encoding"
+)
+
+// Rule 表示一条校验规则。
+type Rule struct {
+	// Name 规则名，如 min、max、pattern、oneof、url、duration
+	Name string
+	// Op 比较运算符，仅 duration 规则使用
+	Op string
+	// Arg 规则参数
+	Arg string
+
+	check func(v reflect.Value) error
+}
+
+// String 返回规则在 tag 中的写法。
+func (r *Rule) String() string {
+	if r.Op != "" {
+		return r.Name + r.Op + r.Arg
+	}
+	if r.Arg != "" {
+		return r.Name + "=" + r.Arg
+	}
+	return r.Name
+}
+
+// Rules 表示一组校验规则。
+type Rules []*Rule
+
+// String 返回规则列表在 tag 中的写法。
+func (rules Rules) String() string {
+	s := make([]string, 0, len(rules))
+	for _, r := range rules {
+		s = append(s, r.String())
+	}
+	return strings.Join(s, ",")
+}
+
+// ParseRules 解析 `validate` tag。
+//
+// 规则以逗号分隔，支持 min=、max=、pattern=、oneof=（空格分隔候选值）、url、
+// duration>=、duration<=、duration>、duration<；pattern 中的逗号会保留，直至遇到下一条规则且正则可编译。
+func ParseRules(tag string) (Rules, error) {
+	rules := make(Rules, 0)
+
+	parts := strings.Split(tag, ",")
+
+	for i := 0; i < len(parts); i++ {
+		part := strings.TrimSpace(parts[i])
+		if part == "" {
+			continue
+		}
+
+		if expr, ok := strings.CutPrefix(part, "pattern="); ok {
+			for i+1 < len(parts) && !(isRuleStart(parts[i+1]) && isRegexp(expr)) {
+				i++
+				expr += "," + parts[i]
+			}
+			part = "pattern=" + expr
+		}
+
+		r, err := parseRule(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validate rule %q: %w", part, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func isRuleStart(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "url" || strings.HasPrefix(s, "duration") {
+		return true
+	}
+	name, _, ok := strings.Cut(s, "=")
+	return ok && slices.Contains([]string{"min", "max", "pattern", "oneof"}, name)
+}
+
+func isRegexp(expr string) bool {
+	_, err := regexp.Compile(expr)
+	return err == nil
+}
+
+func parseRule(s string) (*Rule, error) {
+	if strings.HasPrefix(s, "duration") {
+		expr := strings.TrimPrefix(s, "duration")
+
+		for _, op := range []string{">=", "<=", ">", "<"} {
+			if arg, ok := strings.CutPrefix(expr, op); ok {
+				limit, err := time.ParseDuration(arg)
+				if err != nil {
+					return nil, err
+				}
+				return &Rule{Name: "duration", Op: op, Arg: arg, check: checkDuration(op, limit)}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("missing comparison operator")
+	}
+
+	name, arg, _ := strings.Cut(s, "=")
+
+	r := &Rule{Name: name, Arg: arg}
+
+	switch name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, err
+		}
+		r.check = checkRange(name == "min", limit)
+	case "pattern":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		r.check = func(v reflect.Value) error {
+			if !re.MatchString(textOf(v)) {
+				return fmt.Errorf("does not match")
+			}
+			return nil
+		}
+	case "oneof":
+		values := strings.Fields(arg)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("missing values")
+		}
+		r.check = func(v reflect.Value) error {
+			if !slices.Contains(values, textOf(v)) {
+				return fmt.Errorf("not one of")
+			}
+			return nil
+		}
+	case "url":
+		r.check = func(v reflect.Value) error {
+			u, err := url.Parse(textOf(v))
+			if err != nil {
+				return err
+			}
+			if u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("missing scheme or host")
+			}
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule")
+	}
+
+	return r, nil
+}
+
+// Validate 校验值是否满足全部规则，返回所有未满足的规则；切片会逐个元素校验。
+func (rules Rules) Validate(v reflect.Value) Rules {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return Rules{}
+	}
+
+	if v.Kind() == reflect.Slice {
+		failed := make(Rules, 0)
+		for i := 0; i < v.Len(); i++ {
+			for _, r := range rules.Validate(v.Index(i)) {
+				if !slices.Contains(failed, r) {
+					failed = append(failed, r)
+				}
+			}
+		}
+		return failed
+	}
+
+	failed := make(Rules, 0)
+	for _, r := range rules {
+		if err := r.check(v); err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func checkRange(isMin bool, limit float64) func(v reflect.Value) error {
+	return func(v reflect.Value) error {
+		var n float64
+
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = v.Float()
+		default:
+			// 字符串等按长度比较
+			n = float64(len(textOf(v)))
+		}
+
+		if isMin && n < limit || !isMin && n > limit {
+			return fmt.Errorf("out of range")
+		}
+		return nil
+	}
+}
+
+func checkDuration(op string, limit time.Duration) func(v reflect.Value) error {
+	return func(v reflect.Value) error {
+		var d time.Duration
+
+		if x, ok := v.Interface().(time.Duration); ok {
+			d = x
+		} else {
+			parsed, err := time.ParseDuration(textOf(v))
+			if err != nil {
+				return err
+			}
+			d = parsed
+		}
+
+		ok := false
+		switch op {
+		case ">=":
+			ok = d >= limit
+		case "<=":
+			ok = d <= limit
+		case ">":
+			ok = d > limit
+		case "<":
+			ok = d < limit
+		}
+
+		if !ok {
+			return fmt.Errorf("out of range")
+		}
+		return nil
+	}
+}
+
+func textOf(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	text, _ := encodingx.MarshalText(v)
+	return string(text)
+}
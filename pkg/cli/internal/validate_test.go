@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestParseRules(t *testing.T) {
+	rules := MustValue(t, func() (Rules, error) {
+		return ParseRules("min=1,max=65535,duration>=1s,oneof=a b,url,pattern=^[a-z]{1,3}$")
+	})
+
+	Then(
+		t, "规则按原写法回显，pattern 读取剩余全部内容",
+		Expect(rules.String(), Equal("min=1,max=65535,duration>=1s,oneof=a b,url,pattern=^[a-z]{1,3}$")),
+	)
+
+	t.Run("pattern 位于中间", func(t *testing.T) {
+		rules := MustValue(t, func() (Rules, error) {
+			return ParseRules("pattern=^[a-z]{1,3}$,min=1,pattern=^a,b$,max=3")
+		})
+
+		Then(
+			t, "pattern 中的逗号保留，其后的规则仍然生效",
+			Expect(len(rules), Equal(4)),
+			Expect(rules[0].Arg, Equal("^[a-z]{1,3}$")),
+			Expect(rules[2].Arg, Equal("^a,b$")),
+			Expect(rules.Validate(reflect.ValueOf("abcd")).String(), Equal("pattern=^[a-z]{1,3}$,pattern=^a,b$,max=3")),
+		)
+	})
+
+	_, err := ParseRules("unknown=1")
+
+	Then(
+		t, "未知规则会报错",
+		Expect(err != nil, Equal(true)),
+	)
+}
+
+func TestRulesValidate(t *testing.T) {
+	check := func(tag string, v any) string {
+		rules, err := ParseRules(tag)
+		if err != nil {
+			t.Fatalf("parse rules: %v", err)
+		}
+		return rules.Validate(reflect.ValueOf(v)).String()
+	}
+
+	Then(
+		t, "返回所有未满足的规则",
+		Expect(check("min=1,max=65535", 0), Equal("min=1")),
+		Expect(check("min=1,max=65535", 80), Equal("")),
+		Expect(check("max=3", "abcd"), Equal("max=3")),
+		Expect(check("oneof=a b", []string{"a", "c"}), Equal("oneof=a b")),
+		Expect(check("url", "http://localhost:80"), Equal("")),
+		Expect(check("url", "localhost"), Equal("url")),
+		Expect(check("pattern=^v\\d+$", "v1"), Equal("")),
+		Expect(check("duration>=1s", 500*time.Millisecond), Equal("duration>=1s")),
+		Expect(check("duration>=1s", "2s"), Equal("")),
+		Expect(check("min=1,oneof=a b", ""), Equal("min=1,oneof=a b")),
+	)
+}
+
+func TestFlagVarValidate(t *testing.T) {
+	f := &FlagVar{
+		Name:   "port",
+		EnvVar: "APP_PORT",
+		Rules:  MustValue(t, func() (Rules, error) { return ParseRules("min=1,max=65535") }),
+	}
+
+	err := f.Validate(reflect.ValueOf(70000))
+
+	Then(
+		t, "违规信息包含环境变量、当前值与规则",
+		Expect(err.Error(), Equal(`配置 ${APP_PORT} 的值 "70000" 不满足 max=65535`)),
+		ExpectDo(func() error { return f.Validate(reflect.ValueOf(80)) }),
+	)
+
+	optional := func(tag string) *FlagVar {
+		return &FlagVar{
+			Name:   "value",
+			EnvVar: "APP_VALUE",
+			Rules:  MustValue(t, func() (Rules, error) { return ParseRules(tag) }),
+		}
+	}
+
+	Then(
+		t, "非必填的零值表示未设置，跳过全部规则",
+		ExpectDo(func() error { return f.Validate(reflect.ValueOf(0)) }),
+		ExpectDo(func() error { return optional("duration>=1s").Validate(reflect.ValueOf(time.Duration(0))) }),
+		ExpectDo(func() error { return optional("min=1,url").Validate(reflect.ValueOf("")) }),
+	)
+
+	required := optional("min=1")
+	required.Required = true
+
+	err = required.Validate(reflect.ValueOf(""))
+
+	Then(
+		t, "必填的零值返回缺失必填配置",
+		Expect(err.Error(), Equal("缺失必填配置 ${APP_VALUE}, 可通过环境变量、配置文件或 --value 设置")),
+	)
+}
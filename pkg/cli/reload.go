@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/octohelm/x/logr"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+	"github.com/innoai-tech/infra/pkg/configuration"
+)
+
+// configurationReloader 在重载时重新读取环境变量、env 文件与配置文件，
+// 并把变更写回标记了 reload 且所属配置对象实现了 configuration.Reloader 的字段。
+type configurationReloader struct {
+	envFile    string
+	configFile string
+	flagVars   []*internal.FlagVar
+}
+
+type flagChange struct {
+	flagVar *internal.FlagVar
+	next    reflect.Value
+	source  string
+	ref     string
+}
+
+// Reload 对比新旧配置并应用变更；存在解析或校验错误时不会应用任何变更。
+func (r *configurationReloader) Reload(ctx context.Context) error {
+	sources, err := loadConfigSources(r.envFile, r.configFile, r.flagVars)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]flagChange, 0)
+	errs := make([]error, 0)
+
+	for _, f := range r.flagVars {
+		next, source, err := f.Resolve(sources...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		next, ref, err := resolveSecret(ctx, f, next)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := f.Validate(next); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if reflect.DeepEqual(next.Interface(), f.Value.Interface()) {
+			continue
+		}
+
+		changes = append(changes, flagChange{flagVar: f, next: next, source: source, ref: ref})
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	l := logr.FromContext(ctx)
+
+	applied := make([]any, 0, len(changes)*2)
+	skipped := make([]string, 0)
+
+	for _, change := range changes {
+		f := change.flagVar
+
+		// 仅显式标记为可热更新的字段会被写回，其余变更需重启后生效
+		if _, ok := f.Configurator.(configuration.Reloader); !ok || !f.Reloadable {
+			skipped = append(skipped, f.EnvVar)
+			continue
+		}
+
+		prev := reloadLogValue(f)
+		f.Value.Set(change.next)
+		f.Source = change.source
+		f.SecretRef = change.ref
+
+		applied = append(applied, f.EnvVar, fmt.Sprintf("%s -> %s", prev, reloadLogValue(f)))
+	}
+
+	if len(applied) > 0 {
+		l.WithValues(applied...).Info("configuration reloaded")
+	}
+
+	if len(skipped) > 0 {
+		l.Warn(fmt.Errorf("configuration changed but requires restart: %v", skipped))
+	}
+
+	return nil
+}
+
+// secretMask 为重载日志中敏感值使用的固定长度遮罩，避免泄露敏感值长度。
+const secretMask = "******"
+
+// reloadLogValue 返回重载日志中展示的值，来自引用之外的敏感值以固定遮罩展示。
+func reloadLogValue(f *internal.FlagVar) string {
+	if f.Secret && f.SecretRef == "" {
+		if f.Value.IsZero() {
+			return ""
+		}
+		return secretMask
+	}
+	return f.SecurityString()
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/innoai-tech/infra/pkg/cli/internal"
+)
+
+// SecretResolver 将 secret 引用解析为实际值。
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// SecretResolverFunc 将函数适配为 SecretResolver。
+type SecretResolverFunc func(ctx context.Context, ref *url.URL) (string, error)
+
+// ResolveSecret 调用函数本身。
+func (fn SecretResolverFunc) ResolveSecret(ctx context.Context, ref *url.URL) (string, error) {
+	return fn(ctx, ref)
+}
+
+var secretResolvers sync.Map
+
+// RegisterSecretResolver 为指定 scheme 注册 secret 解析器，重复注册会覆盖。
+//
+// 带 `flag:",secret"` 的字段值以已注册的 scheme 开头时，会在绑定配置后被解析替换。
+// 内置 `file:///path/to/secret` 与 `env:OTHER_VAR`。
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolvers.Store(strings.ToLower(scheme), resolver)
+}
+
+func init() {
+	RegisterSecretResolver("file", SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		data, err := os.ReadFile(ref.Path)
+		if err != nil {
+			return "", err
+		}
+		// secret 挂载文件常带结尾换行
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}))
+
+	RegisterSecretResolver("env", SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		name := ref.Opaque
+		if name == "" {
+			name = ref.Host
+		}
+
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("env var %s not found", name)
+		}
+		return v, nil
+	}))
+}
+
+// resolveSecret 解析 secret flag 值中的引用，返回解析后的值与原始引用；非引用时原样返回且引用为空。
+func resolveSecret(ctx context.Context, f *internal.FlagVar, v reflect.Value) (reflect.Value, string, error) {
+	if !f.Secret {
+		return v, "", nil
+	}
+
+	ref := (&internal.FlagVar{Value: v}).DefaultValue()
+
+	scheme, _, ok := strings.Cut(ref, ":")
+	if !ok {
+		return v, "", nil
+	}
+
+	resolver, ok := secretResolvers.Load(strings.ToLower(scheme))
+	if !ok {
+		return v, "", nil
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return v, "", fmt.Errorf("invalid secret reference of ${%s}: %w", f.EnvVar, err)
+	}
+
+	value, err := resolver.(SecretResolver).ResolveSecret(ctx, u)
+	if err != nil {
+		return v, "", fmt.Errorf("resolve secret ${%s} from %s failed: %w", f.EnvVar, ref, err)
+	}
+
+	resolved, err := f.Parse(value)
+	if err != nil {
+		// 不在错误中携带解析出的值
+		return v, "", fmt.Errorf("resolve secret ${%s} from %s failed: invalid value", f.EnvVar, ref)
+	}
+
+	return resolved, ref, nil
+}
@@ -3,7 +3,13 @@ package configuration
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -415,6 +421,460 @@ func TestLifecycleErrorsIncludeStageAndType(t *testing.T) {
 	)
 }
 
+type orderRecorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *orderRecorder) record(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *orderRecorder) Steps() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.steps)
+}
+
+type orderedProvider struct {
+	name     string
+	recorder *orderRecorder
+}
+
+func (p *orderedProvider) Run(ctx context.Context) error {
+	p.recorder.record("run " + p.name)
+	return nil
+}
+
+func (p *orderedProvider) Shutdown(ctx context.Context) error {
+	// 放慢关闭，确保依赖方不会与之并发
+	time.Sleep(10 * time.Millisecond)
+	p.recorder.record("shutdown " + p.name)
+	return nil
+}
+
+type orderedServer struct {
+	name      string
+	recorder  *orderRecorder
+	dependsOn []any
+}
+
+func (s *orderedServer) DependsOn() []any {
+	return s.dependsOn
+}
+
+func (s *orderedServer) Serve(ctx context.Context) error {
+	return nil
+}
+
+func (s *orderedServer) Shutdown(ctx context.Context) error {
+	s.recorder.record("shutdown " + s.name)
+	return nil
+}
+
+type injectedConsumer struct {
+	provider *orderedProvider `inject:""`
+	recorder *orderRecorder
+}
+
+func (c *injectedConsumer) Run(ctx context.Context) error {
+	c.recorder.record("run consumer")
+	return nil
+}
+
+func (c *injectedConsumer) Shutdown(ctx context.Context) error {
+	c.recorder.record("shutdown consumer")
+	return nil
+}
+
+func TestDependencyLevels(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	provider := &orderedProvider{name: "provider", recorder: r}
+	consumer := &injectedConsumer{recorder: r}
+	api := &orderedServer{name: "api", recorder: r}
+	web := &orderedServer{name: "web", recorder: r, dependsOn: []any{api}}
+
+	levels := MustValue(t, func() ([][]any, error) {
+		return dependencyLevels[any](provider, api, web, consumer)
+	})
+
+	names := make([][]string, 0, len(levels))
+	for _, level := range levels {
+		levelNames := make([]string, 0, len(level))
+		for _, c := range level {
+			levelNames = append(levelNames, fmt.Sprintf("%T", c))
+		}
+		names = append(names, levelNames)
+	}
+
+	Then(
+		t, "依赖来自 inject tag、DependsOn 和 server 对先声明对象的隐式依赖",
+		Expect(names, Equal([][]string{
+			{"*configuration.orderedProvider"},
+			{"*configuration.orderedServer", "*configuration.injectedConsumer"},
+			{"*configuration.orderedServer"},
+		})),
+		Expect(levels[1][0] == any(api), Equal(true)),
+		Expect(levels[2][0] == any(web), Equal(true)),
+	)
+}
+
+func TestDependencyLevelsCycle(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	a := &orderedServer{name: "a", recorder: r}
+	b := &orderedServer{name: "b", recorder: r, dependsOn: []any{a}}
+	a.dependsOn = []any{reflect.TypeFor[*orderedServer]()}
+
+	err := RunOrServe(context.Background(), a, b)
+
+	Then(
+		t, "循环依赖会在启动前报错",
+		Expect(err == nil, Equal(false)),
+		Expect(strings.Contains(err.Error(), "dependency cycle"), Equal(true)),
+	)
+}
+
+func TestRunOrServeDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	consumer := &injectedConsumer{recorder: r}
+	provider := &orderedProvider{name: "provider", recorder: r}
+	server := &orderedServer{name: "server", recorder: r}
+
+	Must(t, func() error {
+		return RunOrServe(context.Background(), consumer, provider, server)
+	})
+
+	Then(
+		t, "runner 按依赖顺序执行，关闭按相反层级逐层完成",
+		Expect(r.Steps(), Equal([]string{
+			"run provider",
+			"run consumer",
+			"shutdown server",
+			"shutdown consumer",
+			"shutdown provider",
+		})),
+	)
+}
+
+type passThroughConfigurator struct {
+	dependsOn []any
+}
+
+func (c *passThroughConfigurator) DependsOn() []any {
+	return c.dependsOn
+}
+
+type dependentProvider struct {
+	*orderedProvider
+	dependsOn []any
+}
+
+func (p *dependentProvider) DependsOn() []any {
+	return p.dependsOn
+}
+
+type immediateProvider struct {
+	name     string
+	recorder *orderRecorder
+}
+
+func (p *immediateProvider) Run(ctx context.Context) error {
+	p.recorder.record("run " + p.name)
+	return nil
+}
+
+func (p *immediateProvider) Shutdown(ctx context.Context) error {
+	p.recorder.record("shutdown " + p.name)
+	return nil
+}
+
+func TestRunOrServeShutdownKeepsTransitiveDependency(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	c := &immediateProvider{name: "c", recorder: r}
+	b := &passThroughConfigurator{dependsOn: []any{c}}
+	a := &dependentProvider{orderedProvider: &orderedProvider{name: "a", recorder: r}, dependsOn: []any{b}}
+
+	Must(t, func() error {
+		return RunOrServe(context.Background(), a, b, c)
+	})
+
+	Then(
+		t, "经由不支持关闭的对象传递的依赖在关闭时仍然生效",
+		Expect(r.Steps(), Equal([]string{
+			"run c",
+			"run a",
+			"shutdown a",
+			"shutdown c",
+		})),
+	)
+}
+
+type readinessServer struct {
+	name     string
+	recorder *orderRecorder
+	ready    chan struct{}
+	readyErr error
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newReadinessServer(name string, recorder *orderRecorder) *readinessServer {
+	return &readinessServer{
+		name:     name,
+		recorder: recorder,
+		ready:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *readinessServer) Serve(ctx context.Context) error {
+	s.recorder.record("serve " + s.name)
+	// 模拟启动耗时
+	time.Sleep(10 * time.Millisecond)
+	s.recorder.record("ready " + s.name)
+	close(s.ready)
+	<-s.done
+	return nil
+}
+
+func (s *readinessServer) WaitReady(ctx context.Context) error {
+	if s.readyErr != nil {
+		return s.readyErr
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.ready:
+		return nil
+	}
+}
+
+func (s *readinessServer) PostServeRun(ctx context.Context) error {
+	s.recorder.record("post-serve " + s.name)
+	// 所有服务就绪后主动结束
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func (s *readinessServer) Shutdown(ctx context.Context) error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+type dependentReadinessServer struct {
+	*readinessServer
+	dependsOn []any
+}
+
+func (s *dependentReadinessServer) DependsOn() []any {
+	return s.dependsOn
+}
+
+func (s *dependentReadinessServer) PostServeRun(ctx context.Context) error {
+	s.recorder.record("post-serve " + s.name)
+	return nil
+}
+
+func TestRunOrServeWaitsReadinessBetweenLevels(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	api := newReadinessServer("api", r)
+	web := &dependentReadinessServer{
+		readinessServer: newReadinessServer("web", r),
+		dependsOn:       []any{api},
+	}
+
+	Must(t, func() error {
+		return RunOrServe(context.Background(), web, api)
+	})
+
+	steps := r.Steps()
+
+	Then(
+		t, "下一层在上一层就绪后启动，post-serve 在全部就绪后执行",
+		Expect(steps[0:4], Equal([]string{
+			"serve api",
+			"ready api",
+			"serve web",
+			"ready web",
+		})),
+		Expect(slices.Contains(steps[4:], "post-serve api"), Equal(true)),
+		Expect(slices.Contains(steps[4:], "post-serve web"), Equal(true)),
+	)
+}
+
+func TestRunOrServeAbortsWhenNotReady(t *testing.T) {
+	t.Parallel()
+
+	r := &orderRecorder{}
+	api := newReadinessServer("api", r)
+	api.readyErr = lifecycleErr
+
+	err := RunOrServe(context.Background(), api)
+
+	Then(
+		t, "就绪检查失败会中止启动并携带阶段和类型信息",
+		ExpectDo(func() error { return err }, ErrorIs(lifecycleErr)),
+		Expect(strings.Contains(err.Error(), "ready *configuration.readinessServer"), Equal(true)),
+		Expect(slices.Contains(r.Steps(), "post-serve api"), Equal(false)),
+	)
+}
+
+type signalServer struct {
+	recorder *orderRecorder
+	done     chan struct{}
+	once     sync.Once
+	onReload func()
+	onStop   func()
+}
+
+func newSignalServer(recorder *orderRecorder) *signalServer {
+	return &signalServer{
+		recorder: recorder,
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *signalServer) Serve(ctx context.Context) error {
+	<-s.done
+	return nil
+}
+
+func (s *signalServer) PostServeRun(ctx context.Context) error {
+	return sendSignal(syscall.SIGHUP)
+}
+
+func (s *signalServer) Reload(ctx context.Context) error {
+	s.recorder.record("reload")
+	if s.onReload != nil {
+		s.onReload()
+	}
+	return nil
+}
+
+func (s *signalServer) Shutdown(ctx context.Context) error {
+	s.recorder.record("shutdown")
+	if s.onStop != nil {
+		s.onStop()
+	}
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func sendSignal(sig os.Signal) error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
+
+func TestSignalPolicy(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultSignalPolicy()
+
+	Then(
+		t, "默认策略只监听 INT/TERM，With 返回副本",
+		Expect(len(p.Signals()), Equal(2)),
+		Expect(ReloadOnHangup()[syscall.SIGHUP], Equal(SignalReload)),
+		Expect(p.With(SignalIgnore, syscall.SIGTERM)[syscall.SIGTERM], Equal(SignalIgnore)),
+		Expect(p[syscall.SIGTERM], Equal(SignalStop)),
+		Expect(SignalPolicyFromContext(context.Background())[os.Interrupt], Equal(SignalStop)),
+	)
+}
+
+func TestRunOrServeReloadOnSignal(t *testing.T) {
+	r := &orderRecorder{}
+	s := newSignalServer(r)
+	s.onReload = func() {
+		_ = sendSignal(syscall.SIGTERM)
+	}
+
+	ctx := SignalPolicyInjectContext(context.Background(), ReloadOnHangup())
+
+	Must(t, func() error {
+		return RunOrServe(ctx, s)
+	})
+
+	Then(
+		t, "SIGHUP 调用 Reloader 后进程继续运行，SIGTERM 优雅关闭",
+		Expect(r.Steps(), Equal([]string{"reload", "shutdown"})),
+	)
+}
+
+func TestRunOrServeStopDuringSlowReload(t *testing.T) {
+	r := &orderRecorder{}
+	s := newSignalServer(r)
+	s.onReload = func() {
+		_ = sendSignal(syscall.SIGTERM)
+		// 重新加载直至服务关闭才返回
+		<-s.done
+	}
+
+	ctx := SignalPolicyInjectContext(context.Background(), ReloadOnHangup())
+
+	Must(t, func() error {
+		return RunOrServe(ctx, s)
+	})
+
+	Then(
+		t, "耗时的重新加载不会阻塞停止信号的处理",
+		Expect(r.Steps(), Equal([]string{"reload", "shutdown"})),
+	)
+}
+
+func TestRunOrServeForceExitOnSecondStopSignal(t *testing.T) {
+	exited := make(chan int, 1)
+
+	exit = func(code int) {
+		exited <- code
+	}
+	t.Cleanup(func() {
+		exit = os.Exit
+	})
+
+	r := &orderRecorder{}
+	s := newSignalServer(r)
+	s.onReload = func() {
+		_ = sendSignal(syscall.SIGTERM)
+	}
+	s.onStop = func() {
+		_ = sendSignal(syscall.SIGINT)
+		<-exited
+		r.record("exit")
+	}
+
+	ctx := SignalPolicyInjectContext(context.Background(), ReloadOnHangup())
+
+	Must(t, func() error {
+		return RunOrServe(ctx, s)
+	})
+
+	Then(
+		t, "优雅关闭期间再次收到停止信号会强制退出",
+		Expect(r.Steps(), Equal([]string{"reload", "shutdown", "exit"})),
+	)
+}
+
 func mustStringValue(v any) string {
 	s, _ := v.(string)
 	return s
@@ -0,0 +1,162 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dependent 表示对象显式声明它所依赖的其他配置对象。
+//
+// DependsOn 返回的元素可以是配置对象实例，也可以是 reflect.Type；
+// 后者会匹配所有可赋值给该类型的配置对象。
+type Dependent interface {
+	DependsOn() []any
+}
+
+// dependencyLevels 按依赖关系将配置对象分层。
+//
+// 依赖来源：
+//   - Dependent.DependsOn 显式声明
+//   - 带 `inject` tag 的字段，匹配类型可赋值的其他配置对象
+//   - Server 隐式依赖在它之前声明的非 Server 对象，因为它消费这些对象注入的上下文
+//
+// 同层对象之间没有依赖；层序即启动顺序，逆序即关闭顺序。存在循环依赖时返回错误。
+func dependencyLevels[T any](configurators ...T) ([][]T, error) {
+	deps := make([][]int, len(configurators))
+
+	for i := range configurators {
+		deps[i] = dependenciesOf(i, configurators)
+	}
+
+	levels := make([][]T, 0)
+	resolved := make([]bool, len(configurators))
+	remain := len(configurators)
+
+	for remain > 0 {
+		level := make([]int, 0)
+
+		for i := range configurators {
+			if resolved[i] {
+				continue
+			}
+
+			ready := true
+			for _, d := range deps[i] {
+				if !resolved[d] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				level = append(level, i)
+			}
+		}
+
+		if len(level) == 0 {
+			cycle := make([]string, 0, remain)
+			for i, c := range configurators {
+				if !resolved[i] {
+					cycle = append(cycle, fmt.Sprintf("%T", c))
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+
+		values := make([]T, 0, len(level))
+		for _, i := range level {
+			resolved[i] = true
+			values = append(values, configurators[i])
+		}
+		remain -= len(level)
+
+		levels = append(levels, values)
+	}
+
+	return levels, nil
+}
+
+func dependenciesOf[T any](i int, configurators []T) []int {
+	target := any(configurators[i])
+
+	matchers := make([]func(c any) bool, 0)
+
+	if d, ok := target.(Dependent); ok {
+		for _, dep := range d.DependsOn() {
+			switch x := dep.(type) {
+			case reflect.Type:
+				matchers = append(matchers, func(c any) bool {
+					return reflect.TypeOf(c).AssignableTo(x)
+				})
+			default:
+				matchers = append(matchers, func(c any) bool {
+					return c == dep
+				})
+			}
+		}
+	}
+
+	for _, t := range injectFieldTypes(reflect.TypeOf(target)) {
+		matchers = append(matchers, func(c any) bool {
+			return reflect.TypeOf(c).AssignableTo(t)
+		})
+	}
+
+	_, isServer := target.(Server)
+
+	deps := make([]int, 0)
+
+	for j, c := range configurators {
+		if j == i {
+			continue
+		}
+
+		if isServer && j < i {
+			if _, ok := any(c).(Server); !ok {
+				deps = append(deps, j)
+				continue
+			}
+		}
+
+		for _, match := range matchers {
+			if match(c) {
+				deps = append(deps, j)
+				break
+			}
+		}
+	}
+
+	return deps
+}
+
+func injectFieldTypes(t reflect.Type) []reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	types := make([]reflect.Type, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+
+		if _, ok := ft.Tag.Lookup("inject"); ok {
+			types = append(types, ft.Type)
+			continue
+		}
+
+		if ft.Anonymous {
+			types = append(types, injectFieldTypes(ft.Type)...)
+		}
+	}
+
+	return types
+}
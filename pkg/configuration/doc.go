@@ -4,6 +4,9 @@
 //   - 统一驱动 `SetDefaults -> Init -> InjectContext -> Run/Serve -> Shutdown`
 //   - 组合多个 configurator 的上下文注入链
 //   - 处理 disabled、shutdown timeout 和 server 生命周期编排
+//   - 基于 `Dependent` 与 `inject` tag 推导依赖，按拓扑顺序启动、逆序逐层关闭
+//   - 通过 `ReadinessProber` 等待每层服务就绪后再启动下一层与 `PostServeRunner`
+//   - 按 `SignalPolicy` 处理信号：默认 INT/TERM 优雅关闭，可选 SIGHUP 调用 `Reloader`
 //
 // 它不负责：
 //   - 决定具体业务对象如何拆分
@@ -2,13 +2,14 @@ package configuration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log/slog"
 	"os"
 	"os/signal"
 	"slices"
-	"syscall"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -28,26 +29,42 @@ func init() {
 	log = slog.New(slog.NewTextHandler(os.Stdout, opt))
 }
 
-// RunOrServe 依次执行 runner，并在需要时启动 server 生命周期。
+// RunOrServe 按依赖顺序执行 runner，并在需要时启动 server 生命周期。
+//
+// server 按依赖层级依次启动，实现 ReadinessProber 的 server 就绪后才会启动下一层；
+// 全部 server 就绪后才执行 PostServeRunner。关闭时按相反层级逐层执行，
+// 每一层都会等待上一层全部关闭完成。
+//
+// 运行期间按上下文中的 SignalPolicy 处理信号，默认 INT/TERM 触发优雅关闭，
+// 关闭期间再次收到停止信号则立即退出进程。
 func RunOrServe(ctx context.Context, configurators ...any) error {
-	configuratorServers := make([]Server, 0, len(configurators))
-	configuratorCanShutdowns := make([]CanShutdown, 0, len(configurators))
+	levels, err := dependencyLevels(configurators...)
+	if err != nil {
+		return err
+	}
 
-	for _, configurator := range configurators {
-		if x, ok := configurator.(Server); ok {
-			configuratorServers = append(configuratorServers, x)
-		}
+	configuratorServers := make([][]Server, 0, len(levels))
 
-		if x, ok := configurator.(CanShutdown); ok {
-			configuratorCanShutdowns = append(configuratorCanShutdowns, x)
+	for _, level := range levels {
+		servers := make([]Server, 0, len(level))
+		for _, configurator := range level {
+			if x, ok := configurator.(Server); ok {
+				servers = append(servers, x)
+			}
 		}
+		configuratorServers = append(configuratorServers, servers)
 	}
 
+	hasCanShutdown := slices.ContainsFunc(configurators, func(configurator any) bool {
+		_, ok := configurator.(CanShutdown)
+		return ok
+	})
+
 	ci := ContextInjectorFromContext(ctx)
 	runtimeCtx := ci.InjectContext(ctx)
 	hasEnabledServer := false
 
-	for _, server := range configuratorServers {
+	for _, server := range slices.Concat(configuratorServers...) {
 		if d, ok := server.(CanDisabled); ok && d.Disabled(runtimeCtx) {
 			continue
 		}
@@ -58,10 +75,12 @@ func RunOrServe(ctx context.Context, configurators ...any) error {
 	if err := run(
 		runtimeCtx,
 		func(yield func(Runner) bool) {
-			for _, configurator := range configurators {
-				if x, ok := configurator.(Runner); ok {
-					if !yield(x) {
-						return
+			for _, level := range levels {
+				for _, configurator := range level {
+					if x, ok := configurator.(Runner); ok {
+						if !yield(x) {
+							return
+						}
 					}
 				}
 			}
@@ -71,35 +90,64 @@ func RunOrServe(ctx context.Context, configurators ...any) error {
 	}
 
 	if hasEnabledServer {
-		chStop := make(chan os.Signal, 1)
+		configuratorReloaders := make([]Reloader, 0)
+		for _, level := range levels {
+			for _, configurator := range level {
+				if x, ok := configurator.(Reloader); ok {
+					configuratorReloaders = append(configuratorReloaders, x)
+				}
+			}
+		}
 
-		signal.Notify(
-			chStop,
-			os.Interrupt, os.Kill,
-			syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT,
-			syscall.SIGILL, syscall.SIGABRT, syscall.SIGFPE, syscall.SIGSEGV,
-		)
+		policy := SignalPolicyFromContext(ctx)
+
+		chSignal := make(chan os.Signal, 1)
+		// 不传信号时 signal.Notify 会转发全部信号，空策略需跳过
+		if signals := policy.Signals(); len(signals) > 0 {
+			signal.Notify(chSignal, signals...)
+			defer signal.Stop(chSignal)
+		}
+
+		chStop := make(chan struct{})
+		stop := sync.OnceFunc(func() {
+			close(chStop)
+		})
 
 		c, cancel := context.WithCancel(runtimeCtx)
+		defer cancel()
+
 		g, gc := errgroup.WithContext(c)
 
+		reloader := &serialReloader{reloaders: configuratorReloaders}
+
+		go func() {
+			for {
+				select {
+				case <-c.Done():
+					return
+				case sig := <-chSignal:
+					handleSignal(gc, sig, policy, chStop, stop, reloader)
+				}
+			}
+		}()
+
 		g.Go(func() error {
 			defer cancel()
 			<-chStop
 
-			return Shutdown(gc, configuratorCanShutdowns...)
+			return shutdownLevels(gc, levels)
 		})
 
 		g.Go(func() error {
-			return serve(gc, chStop, slices.Values(configuratorServers))
+			return serve(gc, stop, configuratorServers)
 		})
 
 		return g.Wait()
 	}
 
-	if len(configuratorCanShutdowns) > 0 {
+	if hasCanShutdown {
 		// 关闭并清理
-		return Shutdown(runtimeCtx, configuratorCanShutdowns...)
+		return shutdownLevels(runtimeCtx, levels)
 	}
 
 	return nil
@@ -123,45 +171,200 @@ func run(ctx context.Context, configuratorRunners iter.Seq[Runner]) error {
 	return nil
 }
 
-func serve(ctx context.Context, stopCh chan os.Signal, configuratorServers iter.Seq[Server]) error {
+func serve(ctx context.Context, stop func(), configuratorServerLevels [][]Server) error {
 	g, c := errgroup.WithContext(ctx)
 
-	for server := range configuratorServers {
-		if d, ok := server.(CanDisabled); ok {
+	g.Go(func() error {
+		postServeRunners := make([]PostServeRunner, 0)
+
+		for _, level := range configuratorServerLevels {
+			probers := make([]Server, 0, len(level))
+
+			for _, server := range level {
+				if d, ok := server.(CanDisabled); ok {
+					if d.Disabled(ctx) {
+						continue
+					}
+				}
+
+				g.Go(func() error {
+					l := log.With(
+						slog.String("type", fmt.Sprintf("%T", server)),
+						slog.String("lifecycle", "Serve"),
+					)
+					l.Debug("serving")
+					defer l.Debug("exit")
+
+					err := server.Serve(c)
+					stop()
+					return wrapLifecycleError("serve", server, err)
+				})
+
+				probers = append(probers, server)
+
+				if r, ok := server.(PostServeRunner); ok {
+					postServeRunners = append(postServeRunners, r)
+				}
+			}
+
+			// 下一层依赖当前层，需等待当前层全部就绪后再启动
+			if err := waitReady(c, probers...); err != nil {
+				stop()
+				return err
+			}
+		}
+
+		for _, r := range postServeRunners {
+			g.Go(func() error {
+				return wrapLifecycleError("post-serve", r, r.PostServeRun(ctx))
+			})
+		}
+
+		return nil
+	})
+
+	return g.Wait()
+}
+
+// exit 用于关闭期间再次收到停止信号时强制退出进程。
+var exit = os.Exit
+
+func handleSignal(ctx context.Context, sig os.Signal, policy SignalPolicy, stopping <-chan struct{}, stop func(), reloader *serialReloader) {
+	l := log.With(slog.String("signal", sig.String()))
+
+	switch policy[sig] {
+	case SignalStop:
+		select {
+		case <-stopping:
+			l.Error("forced exit during graceful shutdown")
+			exit(1)
+		default:
+			l.Debug("graceful shutdown")
+			stop()
+		}
+	case SignalReload:
+		// 重新加载在独立协程中执行，避免耗时的 Reload 阻塞后续停止信号的处理
+		if !reloader.start(ctx, l) {
+			l.Debug("reload in progress, skipped")
+		}
+	default:
+	}
+}
+
+// serialReloader 确保同一时刻只有一次重新加载在执行。
+type serialReloader struct {
+	mu        sync.Mutex
+	reloaders []Reloader
+}
+
+func (r *serialReloader) start(ctx context.Context, l *slog.Logger) bool {
+	if !r.mu.TryLock() {
+		return false
+	}
+
+	go func() {
+		defer r.mu.Unlock()
+
+		l.Debug("reloading")
+
+		if err := reload(ctx, r.reloaders...); err != nil {
+			l.Error("reload failed", slog.String("err", err.Error()))
+		}
+	}()
+
+	return true
+}
+
+func reload(ctx context.Context, reloaders ...Reloader) error {
+	errs := make([]error, 0)
+
+	for _, reloader := range reloaders {
+		if d, ok := reloader.(CanDisabled); ok {
 			if d.Disabled(ctx) {
 				continue
 			}
 		}
 
+		if err := reloader.Reload(ctx); err != nil {
+			errs = append(errs, wrapLifecycleError("reload", reloader, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func waitReady(ctx context.Context, servers ...Server) error {
+	g := &errgroup.Group{}
+
+	for _, server := range servers {
+		p, ok := server.(ReadinessProber)
+		if !ok {
+			continue
+		}
+
 		g.Go(func() error {
 			l := log.With(
 				slog.String("type", fmt.Sprintf("%T", server)),
-				slog.String("lifecycle", "Serve"),
+				slog.String("lifecycle", "Ready"),
 			)
-			l.Debug("serving")
-			defer l.Debug("exit")
+			l.Debug("waiting")
 
-			err := server.Serve(c)
-			go func() {
-				stopCh <- syscall.SIGTERM
-			}()
-			return wrapLifecycleError("serve", server, err)
-		})
+			if err := p.WaitReady(ctx); err != nil {
+				return wrapLifecycleError("ready", server, err)
+			}
 
-		if r, ok := server.(PostServeRunner); ok {
-			g.Go(func() error {
-				return wrapLifecycleError("post-serve", r, r.PostServeRun(ctx))
-			})
-		}
+			l.Debug("ready")
+			return nil
+		})
 	}
 
 	return g.Wait()
 }
 
 // Shutdown 对支持关闭的配置对象执行优雅关闭。
+//
+// 关闭按依赖层级逆序进行：被依赖的对象总是在依赖它的对象关闭完成之后才关闭。
+// 同一层内的对象并发关闭；某一层出错不会跳过后续层级，所有错误会合并返回。
+// 依赖仅在传入的对象之间计算；经由不支持关闭的对象传递的依赖需使用 RunOrServe。
 func Shutdown(c context.Context, configuratorCanShutdowns ...CanShutdown) error {
-	timeout := 10 * time.Second
+	levels, err := dependencyLevels(configuratorCanShutdowns...)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+
+	for _, level := range slices.Backward(levels) {
+		if err := shutdownLevel(c, level); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// shutdownLevels 按全部配置对象计算出的依赖层级逆序关闭，逐层筛选出支持关闭的对象，
+// 以保留经由不支持关闭的对象传递的依赖关系。
+func shutdownLevels(c context.Context, levels [][]any) error {
+	errs := make([]error, 0)
 
+	for _, level := range slices.Backward(levels) {
+		canShutdowns := make([]CanShutdown, 0, len(level))
+		for _, configurator := range level {
+			if x, ok := configurator.(CanShutdown); ok {
+				canShutdowns = append(canShutdowns, x)
+			}
+		}
+
+		if err := shutdownLevel(c, canShutdowns); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func shutdownLevel(c context.Context, configuratorCanShutdowns []CanShutdown) error {
 	g := &errgroup.Group{}
 
 	for _, canShutdown := range configuratorCanShutdowns {
@@ -172,6 +375,7 @@ func Shutdown(c context.Context, configuratorCanShutdowns ...CanShutdown) error
 		}
 
 		g.Go(func() error {
+			timeout := 10 * time.Second
 			if d, ok := canShutdown.(WithShutdownTimeout); ok {
 				timeout = d.ShutdownTimeout(c)
 			}
@@ -264,7 +468,22 @@ type Server interface {
 	Serve(ctx context.Context) error
 }
 
-// PostServeRunner 表示对象在服务启动期间还需要附加运行逻辑。
+// ReadinessProber 表示服务可报告自身是否已就绪。
+//
+// WaitReady 阻塞至服务就绪后返回 nil；无法就绪时返回错误，此时启动流程会被中止。
+type ReadinessProber interface {
+	WaitReady(ctx context.Context) error
+}
+
+// Reloader 表示对象支持在运行期间重新加载。
+//
+// 信号策略将某个信号映射为 SignalReload 时，会按依赖顺序调用所有 Reloader；
+// 重载失败只记录错误，进程继续运行。
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// PostServeRunner 表示对象在所有服务就绪后还需要附加运行逻辑。
 type PostServeRunner interface {
 	PostServeRun(ctx context.Context) error
 }
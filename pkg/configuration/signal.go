@@ -0,0 +1,82 @@
+package configuration
+
+import (
+	"context"
+	"maps"
+	"os"
+	"syscall"
+
+	contextx "github.com/octohelm/x/context"
+)
+
+// SignalAction 表示收到信号后的处理方式。
+type SignalAction int
+
+const (
+	// SignalIgnore 忽略信号。
+	SignalIgnore SignalAction = iota
+	// SignalStop 触发优雅关闭；关闭期间再次收到时立即退出进程。
+	SignalStop
+	// SignalReload 调用所有 Reloader，进程继续运行。
+	SignalReload
+)
+
+// SignalPolicy 描述服务运行期间监听的信号及其处理方式。
+type SignalPolicy map[os.Signal]SignalAction
+
+// DefaultSignalPolicy 返回默认信号策略：INT/TERM 触发优雅关闭。
+func DefaultSignalPolicy() SignalPolicy {
+	return SignalPolicy{
+		os.Interrupt:    SignalStop,
+		syscall.SIGTERM: SignalStop,
+	}
+}
+
+// ReloadOnHangup 返回在默认策略基础上由 SIGHUP 触发重载的信号策略。
+func ReloadOnHangup() SignalPolicy {
+	return DefaultSignalPolicy().With(SignalReload, syscall.SIGHUP)
+}
+
+// With 返回追加了给定信号处理方式的策略副本。
+func (p SignalPolicy) With(action SignalAction, signals ...os.Signal) SignalPolicy {
+	policy := maps.Clone(p)
+	if policy == nil {
+		policy = SignalPolicy{}
+	}
+
+	for _, sig := range signals {
+		if action == SignalIgnore {
+			delete(policy, sig)
+			continue
+		}
+		policy[sig] = action
+	}
+
+	return policy
+}
+
+// Signals 返回策略中需要监听的信号。
+func (p SignalPolicy) Signals() []os.Signal {
+	signals := make([]os.Signal, 0, len(p))
+	for sig, action := range p {
+		if action != SignalIgnore {
+			signals = append(signals, sig)
+		}
+	}
+	return signals
+}
+
+type signalPolicyCtx struct{}
+
+// SignalPolicyInjectContext 将信号策略放入上下文。
+func SignalPolicyInjectContext(ctx context.Context, p SignalPolicy) context.Context {
+	return contextx.WithValue(ctx, signalPolicyCtx{}, p)
+}
+
+// SignalPolicyFromContext 从上下文中读取信号策略，缺省时返回 DefaultSignalPolicy。
+func SignalPolicyFromContext(ctx context.Context) SignalPolicy {
+	if p, ok := ctx.Value(signalPolicyCtx{}).(SignalPolicy); ok {
+		return p
+	}
+	return DefaultSignalPolicy()
+}
@@ -0,0 +1,110 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle 汇总同一应用的多个组件，使组件的 EnvVar.ValueRef 可引用其他组件的端口与服务名。
+type Bundle struct {
+	// Name 应用名
+	Name string
+	// Components 组件，key 为 Container.Name
+	Components map[string]*Container
+}
+
+// NewBundle 创建 Bundle 并添加组件。
+func NewBundle(name string, containers ...*Container) (*Bundle, error) {
+	b := &Bundle{
+		Name:       name,
+		Components: map[string]*Container{},
+	}
+
+	for _, c := range containers {
+		if err := b.Add(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// Add 添加组件；组件名为空或重复时报错。
+func (b *Bundle) Add(c *Container) error {
+	if c.Name == "" {
+		return errors.New("container name is required")
+	}
+
+	if b.Components == nil {
+		b.Components = map[string]*Container{}
+	}
+
+	if _, ok := b.Components[c.Name]; ok {
+		return fmt.Errorf("component %s already exists in bundle %s", c.Name, b.Name)
+	}
+
+	c.bundle = b
+	b.Components[c.Name] = c
+
+	return nil
+}
+
+// Validate 解析全部组件的环境变量，汇总无法解析的引用。
+func (b *Bundle) Validate() error {
+	errs := make([]error, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(b.Components)) {
+		if _, err := b.Components[name].ResolveEnv(); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RenderKubernetes 依组件名顺序渲染全部组件的 Kubernetes 资源。
+func (b *Bundle) RenderKubernetes(w io.Writer) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+
+	for _, name := range slices.Sorted(maps.Keys(b.Components)) {
+		objects, err := KubernetesObjects(b.Components[name])
+		if err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			if err := e.Encode(o); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.Close()
+}
+
+// RenderCompose 将全部组件渲染为同一个 compose 文件中的服务，服务名即组件名，可直接互相访问。
+func (b *Bundle) RenderCompose(w io.Writer) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	file := &composeFile{Services: map[string]*composeService{}}
+
+	for name, c := range b.Components {
+		if err := file.addService(c); err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+	}
+
+	return file.encode(w)
+}
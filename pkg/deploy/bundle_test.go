@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	webapp := &Container{
+		Name:      "webapp",
+		ImageName: "ghcr.io/octohelm/webapp",
+		Env: map[string]EnvVar{
+			"WEBAPP_API":  {ValueRef: `http://{{ (component "example").Address "http" }}`},
+			"WEBAPP_PORT": {ValueRef: `{{ (index (index .Components "example").Ports "http").Port }}`},
+		},
+	}
+
+	b := MustValue(t, func() (*Bundle, error) {
+		return NewBundle("example", newTestContainer(), webapp)
+	})
+
+	api := MustValue(t, func() (string, error) {
+		return webapp.Env["WEBAPP_API"].ToValue(webapp)
+	})
+
+	port := MustValue(t, func() (string, error) {
+		return webapp.Env["WEBAPP_PORT"].ToValue(webapp)
+	})
+
+	out := &bytes.Buffer{}
+	Must(t, func() error {
+		return b.RenderCompose(out)
+	})
+
+	Then(
+		t, "组件的环境变量可引用其他组件的服务名与端口",
+		Expect(api, Equal("http://example:8080")),
+		Expect(port, Equal("8080")),
+		Expect(strings.Contains(out.String(), "WEBAPP_API: http://example:8080\n"), Equal(true)),
+	)
+}
+
+func TestBundleValidate(t *testing.T) {
+	t.Parallel()
+
+	b := MustValue(t, func() (*Bundle, error) {
+		return NewBundle("example", &Container{
+			Name: "webapp",
+			Env: map[string]EnvVar{
+				"WEBAPP_API": {ValueRef: `{{ (component "missing").Address "http" }}`},
+			},
+		})
+	})
+
+	err := b.Validate()
+
+	Then(
+		t, "引用不存在的组件时校验失败",
+		Expect(err != nil, Equal(true)),
+		Expect(strings.Contains(err.Error(), "component missing not found"), Equal(true)),
+	)
+
+	_, err = NewBundle("example", &Container{Name: "webapp"}, &Container{Name: "webapp"})
+
+	Then(
+		t, "组件名重复时报错",
+		Expect(err != nil, Equal(true)),
+	)
+}
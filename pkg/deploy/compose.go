@@ -0,0 +1,199 @@
+package deploy
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenderCompose 将 Container 渲染为 docker compose 文件，包含一个以 Container.Name 命名的服务。
+//
+// Ports 按同端口号映射到宿主机，Container.Healthcheck 非空时作为 healthcheck 在容器内执行。
+func RenderCompose(w io.Writer, c *Container) error {
+	file := &composeFile{Services: map[string]*composeService{}}
+
+	if err := file.addService(c); err != nil {
+		return err
+	}
+
+	return file.encode(w)
+}
+
+func (file *composeFile) addService(c *Container) error {
+	if c.Name == "" {
+		return errors.New("container name is required")
+	}
+
+	env, err := c.ResolveEnv()
+	if err != nil {
+		return err
+	}
+
+	service := &composeService{
+		Image:       c.Image(),
+		Entrypoint:  c.Command,
+		Command:     c.Args,
+		Environment: env,
+		Labels:      c.Labels,
+		Restart:     "unless-stopped",
+	}
+
+	file.Services[c.Name] = service
+
+	for _, m := range c.Mounts {
+		v, ok := c.Volumes[m.Volume]
+		if !ok {
+			return fmt.Errorf("mount %s refers to undefined volume %s", m.MountPath, m.Volume)
+		}
+
+		switch v.Type {
+		case "", VolumeEmptyDir:
+			service.Tmpfs = append(service.Tmpfs, m.MountPath)
+		case VolumeHostPath:
+			service.Volumes = append(service.Volumes, composeMount(v.Source, m))
+		case VolumePersistentVolumeClaim:
+			source := cmp.Or(v.Source, m.Volume)
+			if file.Volumes == nil {
+				file.Volumes = map[string]struct{}{}
+			}
+			file.Volumes[source] = struct{}{}
+			service.Volumes = append(service.Volumes, composeMount(source, m))
+		case VolumeSecret, VolumeConfigMap:
+			// 以 compose 文件旁的同名目录代替 Secret/ConfigMap
+			service.Volumes = append(service.Volumes, composeMount("./"+cmp.Or(v.Source, m.Volume), m))
+		default:
+			return fmt.Errorf("unsupported volume type %s of %s in compose", v.Type, m.Volume)
+		}
+	}
+
+	if r := c.Resources; r != nil {
+		service.Deploy = &composeDeploy{}
+		service.Deploy.Resources.Limits = composeResources(r.Limits)
+		service.Deploy.Resources.Reservations = composeResources(r.Requests)
+	}
+
+	if sc := c.SecurityContext; sc != nil {
+		service.ReadOnly = sc.ReadOnlyRootFilesystem
+
+		if sc.RunAsUser != nil {
+			service.User = strconv.FormatInt(*sc.RunAsUser, 10)
+			if sc.RunAsGroup != nil {
+				service.User += ":" + strconv.FormatInt(*sc.RunAsGroup, 10)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Ports)) {
+		p := c.Ports[name]
+		service.Ports = append(service.Ports, fmt.Sprintf("%d:%d/%s", p.Port, p.Port, strings.ToLower(p.protocol())))
+	}
+
+	if len(c.Healthcheck) > 0 {
+		service.Healthcheck = &composeHealthcheck{
+			Test:        append([]string{"CMD"}, c.Healthcheck...),
+			Interval:    "10s",
+			Timeout:     "1s",
+			Retries:     3,
+			StartPeriod: "5s",
+		}
+	}
+
+	return nil
+}
+
+func (file *composeFile) encode(w io.Writer) error {
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+
+	if err := e.Encode(file); err != nil {
+		return err
+	}
+
+	return e.Close()
+}
+
+func composeMount(source string, m Mount) string {
+	target := source + ":" + m.MountPath
+	if m.ReadOnly {
+		target += ":ro"
+	}
+	return target
+}
+
+func composeResources(l ResourceList) *composeResourceList {
+	if l.IsZero() {
+		return nil
+	}
+	return &composeResourceList{
+		CPUs:   composeCPUs(l.CPU),
+		Memory: composeMemory(l.Memory),
+	}
+}
+
+// composeCPUs 将 "500m" 形式的 CPU 数量转为 compose 使用的小数核数。
+func composeCPUs(cpu string) string {
+	if milli, ok := strings.CutSuffix(cpu, "m"); ok {
+		if n, err := strconv.ParseFloat(milli, 64); err == nil {
+			return strconv.FormatFloat(n/1000, 'f', -1, 64)
+		}
+	}
+	return cpu
+}
+
+// composeMemory 将 "128Mi" 形式的内存数量转为 compose 使用的单位。
+func composeMemory(memory string) string {
+	for suffix, unit := range map[string]string{"Ki": "k", "Mi": "m", "Gi": "g"} {
+		if n, ok := strings.CutSuffix(memory, suffix); ok {
+			return n + unit
+		}
+	}
+	return memory
+}
+
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+	Volumes  map[string]struct{}        `yaml:"volumes,omitempty"`
+}
+
+type composeService struct {
+	Image       string              `yaml:"image"`
+	Entrypoint  []string            `yaml:"entrypoint,omitempty"`
+	Command     []string            `yaml:"command,omitempty"`
+	Ports       []string            `yaml:"ports,omitempty"`
+	Environment map[string]string   `yaml:"environment,omitempty"`
+	Volumes     []string            `yaml:"volumes,omitempty"`
+	Tmpfs       []string            `yaml:"tmpfs,omitempty"`
+	ReadOnly    bool                `yaml:"read_only,omitempty"`
+	User        string              `yaml:"user,omitempty"`
+	Labels      map[string]string   `yaml:"labels,omitempty"`
+	Deploy      *composeDeploy      `yaml:"deploy,omitempty"`
+	Healthcheck *composeHealthcheck `yaml:"healthcheck,omitempty"`
+	Restart     string              `yaml:"restart,omitempty"`
+}
+
+type composeDeploy struct {
+	Resources struct {
+		Limits       *composeResourceList `yaml:"limits,omitempty"`
+		Reservations *composeResourceList `yaml:"reservations,omitempty"`
+	} `yaml:"resources"`
+}
+
+type composeResourceList struct {
+	CPUs   string `yaml:"cpus,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+}
+
+type composeHealthcheck struct {
+	Test        []string `yaml:"test"`
+	Interval    string   `yaml:"interval,omitempty"`
+	Timeout     string   `yaml:"timeout,omitempty"`
+	Retries     int      `yaml:"retries,omitempty"`
+	StartPeriod string   `yaml:"start_period,omitempty"`
+}
@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestRenderCompose(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+	Must(t, func() error {
+		return RenderCompose(b, newTestContainer())
+	})
+
+	out := b.String()
+
+	Then(
+		t, "生成 compose 服务，解析模板并映射端口与 healthcheck",
+		Expect(strings.Contains(out, "  example:\n    image: ghcr.io/octohelm/example:1.0.0\n"), Equal(true)),
+		Expect(strings.Contains(out, "EXAMPLE_ADDR: :8080\n"), Equal(true)),
+		Expect(strings.Contains(out, "- 8080:8080/tcp\n"), Equal(true)),
+		Expect(strings.Contains(out, "test:\n        - CMD\n        - example\n        - probe\n        - http://127.0.0.1:8080/ready\n"), Equal(true)),
+		Expect(strings.Contains(out, "- /srv/example:/var/lib/example\n"), Equal(true)),
+		Expect(strings.Contains(out, "read_only: true\n"), Equal(true)),
+		Expect(strings.Contains(out, "cpus: \"0.5\"\n"), Equal(true)),
+	)
+
+	t.Run("未配置 healthcheck", func(t *testing.T) {
+		c := newTestContainer()
+		c.Healthcheck = nil
+
+		b := &bytes.Buffer{}
+		Must(t, func() error {
+			return RenderCompose(b, c)
+		})
+
+		Then(
+			t, "不生成 healthcheck",
+			Expect(strings.Contains(b.String(), "healthcheck:"), Equal(false)),
+		)
+	})
+
+	t.Run("Secret 卷", func(t *testing.T) {
+		c := newTestContainer()
+		c.Volumes = map[string]Volume{"tls-cert-file": {Type: VolumeSecret, Source: "example-tls-cert-file"}}
+		c.Mounts = []Mount{{Volume: "tls-cert-file", MountPath: "/etc/example/tls", ReadOnly: true}}
+
+		b := &bytes.Buffer{}
+		Must(t, func() error {
+			return RenderCompose(b, c)
+		})
+
+		Then(
+			t, "以同名目录只读挂载",
+			Expect(strings.Contains(b.String(), "- ./example-tls-cert-file:/etc/example/tls:ro\n"), Equal(true)),
+		)
+	})
+}
+
+func TestContainerReadinessURL(t *testing.T) {
+	t.Parallel()
+
+	u, ok := newTestContainer().ReadinessURL()
+
+	Then(
+		t, "返回首个就绪探针端口在本机的地址",
+		Expect(ok, Equal(true)),
+		Expect(u, Equal("http://127.0.0.1:8080/ready")),
+	)
+}
@@ -2,6 +2,11 @@ package deploy
 
 import (
 	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
 	"text/template"
 )
 
@@ -23,20 +28,29 @@ type Port struct {
 type EnvVar struct {
 	// Value 静态值
 	Value string
-	// ValueRef Go template，以 Container 为数据上下文
+	// ValueRef Go template，以 Container 为数据上下文，map 字段可写为 `{{ .Ports["http"].Port }}`；
+	// 在 Bundle 中还可经 .Components 或 component 函数引用其他组件，
+	// 如 `{{ (component "example").Address "http" }}`
 	ValueRef string
+	// Secret 是否为敏感值，Kubernetes 中以 secretKeyRef 引用外部管理的 Secret
+	Secret bool
 }
 
 // ToValue 解析环境变量的实际值。
 // 优先使用 ValueRef 执行模板，若为空则返回 Value。
 func (e EnvVar) ToValue(c *Container) (string, error) {
 	if e.ValueRef != "" {
-		t, err := template.New("").Parse(e.ValueRef)
+		ctx := &templateContext{Container: c}
+		if c.bundle != nil {
+			ctx.Components = c.bundle.Components
+		}
+
+		t, err := template.New("").Funcs(template.FuncMap{"component": ctx.component}).Parse(rewriteIndexExpr(e.ValueRef))
 		if err != nil {
 			return "", err
 		}
 		b := &bytes.Buffer{}
-		if err := t.Execute(b, c); err != nil {
+		if err := t.Execute(b, ctx); err != nil {
 			return "", err
 		}
 		return b.String(), nil
@@ -44,8 +58,44 @@ func (e EnvVar) ToValue(c *Container) (string, error) {
 	return e.Value, nil
 }
 
+// indexExpr 匹配 .Ports["http"] 形式的 map 取值。
+var indexExpr = regexp.MustCompile(`((?:\([^()]*\))?(?:\.\w+)+)\["([^"]*)"\]`)
+
+// rewriteIndexExpr 将 .Ports["http"] 形式的 map 取值逐个转换为 text/template 支持的 (index .Ports "http")。
+func rewriteIndexExpr(s string) string {
+	for {
+		loc := indexExpr.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return s
+		}
+		s = s[:loc[0]] + "(index " + s[loc[2]:loc[3]] + ` "` + s[loc[4]:loc[5]] + `")` + s[loc[1]:]
+	}
+}
+
+// templateContext 为 ValueRef 模板的数据上下文。
+type templateContext struct {
+	*Container
+	// Components 所在 Bundle 的全部组件，key 为组件名
+	Components map[string]*Container
+}
+
+func (ctx *templateContext) component(name string) (*Container, error) {
+	if ctx.Components == nil {
+		return nil, fmt.Errorf("component %s is not available outside of a bundle", name)
+	}
+	c, ok := ctx.Components[name]
+	if !ok {
+		return nil, fmt.Errorf("component %s not found in bundle", name)
+	}
+	return c, nil
+}
+
 // Container 描述一个与平台无关的容器运行规格。
 type Container struct {
+	// Name 组件名，用作渲染出的资源名
+	Name string
+	// Kind 工作负载类型，"Deployment"（默认）或 "StatefulSet"
+	Kind string
 	// ImageName 镜像名，如 "ghcr.io/octohelm/example"
 	ImageName string
 	// Version 版本号，如 "1.0.0"
@@ -58,4 +108,140 @@ type Container struct {
 	Ports map[string]Port
 	// Env 环境变量，key 为变量名
 	Env map[string]EnvVar
+	// Volumes 存储卷，key 为卷名
+	Volumes map[string]Volume
+	// Mounts 卷挂载
+	Mounts []Mount
+	// Resources 资源请求与限制
+	Resources *Resources
+	// SecurityContext 安全上下文
+	SecurityContext *SecurityContext
+	// Labels 附加到资源上的标签
+	Labels map[string]string
+	// Annotations 附加到资源上的注解
+	Annotations map[string]string
+	// SecretName Kubernetes 中 Secret 环境变量引用的外部 Secret 名；为空时渲染并引用名为 Name 的 Secret
+	SecretName string
+	// Healthcheck 就绪检查命令，退出码为 0 表示就绪，用于 compose healthcheck 与 systemd 启动等待，
+	// 为空时不生成；Kubernetes 使用端口的 httpGet 探针
+	Healthcheck []string
+
+	// 所属 Bundle，供 ValueRef 引用其他组件
+	bundle *Bundle
+}
+
+// ServiceName 返回组件对外的服务名，即 Container.Name。
+func (c *Container) ServiceName() string {
+	return c.Name
+}
+
+// Address 返回通过服务名访问指定端口的地址，如 "example:80"。
+func (c *Container) Address(portName string) (string, error) {
+	p, ok := c.Ports[portName]
+	if !ok {
+		return "", fmt.Errorf("port %s not found in component %s", portName, c.Name)
+	}
+	return fmt.Sprintf("%s:%d", c.ServiceName(), p.Port), nil
+}
+
+// 存储卷类型。
+const (
+	VolumeEmptyDir              = "emptyDir"
+	VolumeHostPath              = "hostPath"
+	VolumePersistentVolumeClaim = "persistentVolumeClaim"
+	VolumeConfigMap             = "configMap"
+	VolumeSecret                = "secret"
+)
+
+// Volume 描述一个存储卷。
+type Volume struct {
+	// Type 卷类型，默认 "emptyDir"
+	Type string
+	// Source hostPath 的宿主机路径，或被引用的 PVC/ConfigMap/Secret 名
+	Source string
+}
+
+// Mount 描述一个卷挂载。
+type Mount struct {
+	// Volume 卷名，对应 Container.Volumes 的 key
+	Volume string
+	// MountPath 容器内挂载路径
+	MountPath string
+	// SubPath 卷内子路径
+	SubPath string
+	// ReadOnly 是否只读挂载
+	ReadOnly bool
+}
+
+// Resources 描述资源请求与限制。
+type Resources struct {
+	// Requests 资源请求
+	Requests ResourceList
+	// Limits 资源限制
+	Limits ResourceList
+}
+
+// ResourceList 描述各类资源的数量。
+type ResourceList struct {
+	// CPU 如 "100m"
+	CPU string
+	// Memory 如 "128Mi"
+	Memory string
+}
+
+// IsZero 判断是否未设置任何资源。
+func (l ResourceList) IsZero() bool {
+	return l.CPU == "" && l.Memory == ""
+}
+
+// SecurityContext 描述容器的安全设置。
+type SecurityContext struct {
+	// ReadOnlyRootFilesystem 根文件系统只读
+	ReadOnlyRootFilesystem bool
+	// RunAsNonRoot 禁止以 root 运行
+	RunAsNonRoot bool
+	// RunAsUser 运行用户 UID
+	RunAsUser *int64
+	// RunAsGroup 运行用户组 GID
+	RunAsGroup *int64
+}
+
+// Image 返回带版本号的完整镜像地址。
+func (c *Container) Image() string {
+	if c.Version == "" || strings.Contains(c.ImageName, "@") {
+		return c.ImageName
+	}
+	return c.ImageName + ":" + c.Version
+}
+
+// ResolveEnv 解析全部环境变量的实际值。
+func (c *Container) ResolveEnv() (map[string]string, error) {
+	values := make(map[string]string, len(c.Env))
+
+	for name, env := range c.Env {
+		v, err := env.ToValue(c)
+		if err != nil {
+			return nil, fmt.Errorf("resolve env %s failed: %w", name, err)
+		}
+		values[name] = v
+	}
+
+	return values, nil
+}
+
+// ReadinessURL 返回按端口名排序后第一个声明了就绪探针的端口在本机的访问地址，如 "http://127.0.0.1:8080/ready"。
+func (c *Container) ReadinessURL() (string, bool) {
+	for _, name := range slices.Sorted(maps.Keys(c.Ports)) {
+		if p := c.Ports[name]; p.ReadinessEndpoint != "" {
+			return fmt.Sprintf("http://127.0.0.1:%d%s", p.Port, p.ReadinessEndpoint), true
+		}
+	}
+	return "", false
+}
+
+func (p Port) protocol() string {
+	if p.Protocol == "" {
+		return "TCP"
+	}
+	return strings.ToUpper(p.Protocol)
 }
@@ -0,0 +1,437 @@
+package deploy
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenderKubernetes 将 Container 渲染为 Kubernetes 多文档 YAML。
+//
+// 输出依次为 ConfigMap、Secret、Service 与 Deployment/StatefulSet，资源名均为 Container.Name；
+// 普通环境变量经 ConfigMap 注入，Secret 环境变量以 secretKeyRef 引用 Secret。
+// 未指定 Container.SecretName 时渲染同名 Secret，值为 secret 引用（如 file://），未配置引用时以空值占位；
+// 指定时引用外部管理的 Secret，不渲染 Secret。
+func RenderKubernetes(w io.Writer, c *Container) error {
+	objects, err := KubernetesObjects(c)
+	if err != nil {
+		return err
+	}
+
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+
+	for _, o := range objects {
+		if err := e.Encode(o); err != nil {
+			return err
+		}
+	}
+
+	return e.Close()
+}
+
+// KubernetesObjects 返回 Container 对应的 Kubernetes 资源对象，可直接序列化为 YAML 或 JSON。
+func KubernetesObjects(c *Container) ([]any, error) {
+	if c.Name == "" {
+		return nil, errors.New("container name is required")
+	}
+
+	kind := c.Kind
+	if kind == "" {
+		kind = "Deployment"
+	}
+
+	if kind != "Deployment" && kind != "StatefulSet" {
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name": c.Name,
+	}
+
+	meta := func() k8sObjectMeta {
+		m := k8sObjectMeta{
+			Name:        c.Name,
+			Labels:      maps.Clone(c.Labels),
+			Annotations: maps.Clone(c.Annotations),
+		}
+		if m.Labels == nil {
+			m.Labels = map[string]string{}
+		}
+		maps.Copy(m.Labels, labels)
+		if c.Version != "" {
+			m.Labels["app.kubernetes.io/version"] = c.Version
+		}
+		return m
+	}
+
+	configData := map[string]string{}
+
+	container := k8sContainer{
+		Name:    c.Name,
+		Image:   c.Image(),
+		Command: c.Command,
+		Args:    c.Args,
+	}
+
+	for _, m := range c.Mounts {
+		if _, ok := c.Volumes[m.Volume]; !ok {
+			return nil, fmt.Errorf("mount %s refers to undefined volume %s", m.MountPath, m.Volume)
+		}
+
+		container.VolumeMounts = append(container.VolumeMounts, k8sVolumeMount{
+			Name:      m.Volume,
+			MountPath: m.MountPath,
+			SubPath:   m.SubPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+
+	if r := c.Resources; r != nil {
+		container.Resources = &k8sResources{
+			Requests: k8sResourceList(r.Requests),
+			Limits:   k8sResourceList(r.Limits),
+		}
+	}
+
+	if sc := c.SecurityContext; sc != nil {
+		container.SecurityContext = &k8sSecurityContext{
+			ReadOnlyRootFilesystem: sc.ReadOnlyRootFilesystem,
+			RunAsNonRoot:           sc.RunAsNonRoot,
+			RunAsUser:              sc.RunAsUser,
+			RunAsGroup:             sc.RunAsGroup,
+		}
+	}
+
+	values, err := c.ResolveEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	secretName := cmp.Or(c.SecretName, c.Name)
+	secretData := map[string]string{}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if c.Env[name].Secret {
+			container.Env = append(container.Env, k8sEnvVar{
+				Name: name,
+				ValueFrom: &k8sEnvVarSource{
+					SecretKeyRef: &k8sKeySelector{Name: secretName, Key: name},
+				},
+			})
+			secretData[name] = values[name]
+			continue
+		}
+
+		configData[name] = values[name]
+	}
+
+	objects := make([]any, 0, 4)
+
+	if len(configData) > 0 {
+		container.EnvFrom = append(container.EnvFrom, k8sEnvFromSource{
+			ConfigMapRef: &k8sLocalObjectReference{Name: c.Name},
+		})
+
+		objects = append(objects, &k8sConfigMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata:   meta(),
+			Data:       configData,
+		})
+	}
+
+	if len(secretData) > 0 && c.SecretName == "" {
+		objects = append(objects, &k8sSecret{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata:   meta(),
+			Type:       "Opaque",
+			StringData: secretData,
+		})
+	}
+
+	if len(c.Ports) > 0 {
+		service := &k8sService{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   meta(),
+		}
+		service.Spec.Selector = labels
+
+		for _, name := range slices.Sorted(maps.Keys(c.Ports)) {
+			p := c.Ports[name]
+
+			protocol := p.protocol()
+
+			container.Ports = append(container.Ports, k8sContainerPort{
+				Name:          name,
+				ContainerPort: p.Port,
+				Protocol:      protocol,
+			})
+
+			service.Spec.Ports = append(service.Spec.Ports, k8sServicePort{
+				Name:       name,
+				Port:       p.Port,
+				TargetPort: name,
+				Protocol:   protocol,
+			})
+
+			// 同一类探针只取按端口名排序后的第一个
+			if p.ReadinessEndpoint != "" && container.ReadinessProbe == nil {
+				container.ReadinessProbe = httpProbe(p.ReadinessEndpoint, name)
+			}
+			if p.LivenessEndpoint != "" && container.LivenessProbe == nil {
+				container.LivenessProbe = httpProbe(p.LivenessEndpoint, name)
+			}
+		}
+
+		objects = append(objects, service)
+	}
+
+	workload := &k8sWorkload{
+		APIVersion: "apps/v1",
+		Kind:       kind,
+		Metadata:   meta(),
+	}
+
+	podLabels := maps.Clone(c.Labels)
+	if podLabels == nil {
+		podLabels = map[string]string{}
+	}
+	maps.Copy(podLabels, labels)
+
+	workload.Spec.Selector.MatchLabels = labels
+	workload.Spec.Template.Metadata.Labels = podLabels
+	workload.Spec.Template.Metadata.Annotations = c.Annotations
+	workload.Spec.Template.Spec.Containers = []k8sContainer{container}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Volumes)) {
+		v, err := k8sVolumeOf(name, c.Volumes[name])
+		if err != nil {
+			return nil, err
+		}
+		workload.Spec.Template.Spec.Volumes = append(workload.Spec.Template.Spec.Volumes, v)
+	}
+
+	if kind == "StatefulSet" {
+		workload.Spec.ServiceName = c.Name
+	}
+
+	return append(objects, workload), nil
+}
+
+func httpProbe(path string, port string) *k8sProbe {
+	return &k8sProbe{
+		HTTPGet: &k8sHTTPGetAction{
+			Path:   path,
+			Port:   port,
+			Scheme: "HTTP",
+		},
+		InitialDelaySeconds: 5,
+		TimeoutSeconds:      1,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+}
+
+func k8sVolumeOf(name string, v Volume) (k8sVolume, error) {
+	vol := k8sVolume{Name: name}
+
+	switch v.Type {
+	case "", VolumeEmptyDir:
+		vol.EmptyDir = &struct{}{}
+	case VolumeHostPath:
+		vol.HostPath = &k8sHostPathVolumeSource{Path: v.Source}
+	case VolumePersistentVolumeClaim:
+		vol.PersistentVolumeClaim = &k8sPersistentVolumeClaimVolumeSource{ClaimName: v.Source}
+	case VolumeConfigMap:
+		vol.ConfigMap = &k8sLocalObjectReference{Name: v.Source}
+	case VolumeSecret:
+		vol.Secret = &k8sSecretVolumeSource{SecretName: v.Source}
+	default:
+		return vol, fmt.Errorf("unsupported volume type %s of %s", v.Type, name)
+	}
+
+	return vol, nil
+}
+
+func k8sResourceList(l ResourceList) map[string]string {
+	if l.IsZero() {
+		return nil
+	}
+
+	m := map[string]string{}
+	if l.CPU != "" {
+		m["cpu"] = l.CPU
+	}
+	if l.Memory != "" {
+		m["memory"] = l.Memory
+	}
+	return m
+}
+
+type k8sObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type k8sConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sObjectMeta     `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+type k8sSecret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sObjectMeta     `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+type k8sService struct {
+	APIVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Metadata   k8sObjectMeta `yaml:"metadata"`
+	Spec       struct {
+		Selector map[string]string `yaml:"selector"`
+		Ports    []k8sServicePort  `yaml:"ports"`
+	} `yaml:"spec"`
+}
+
+type k8sServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+type k8sWorkload struct {
+	APIVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Metadata   k8sObjectMeta `yaml:"metadata"`
+	Spec       struct {
+		ServiceName string `yaml:"serviceName,omitempty"`
+		Selector    struct {
+			MatchLabels map[string]string `yaml:"matchLabels"`
+		} `yaml:"selector"`
+		Template struct {
+			Metadata struct {
+				Labels      map[string]string `yaml:"labels"`
+				Annotations map[string]string `yaml:"annotations,omitempty"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Containers []k8sContainer `yaml:"containers"`
+				Volumes    []k8sVolume    `yaml:"volumes,omitempty"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+type k8sContainer struct {
+	Name            string              `yaml:"name"`
+	Image           string              `yaml:"image"`
+	Command         []string            `yaml:"command,omitempty"`
+	Args            []string            `yaml:"args,omitempty"`
+	Ports           []k8sContainerPort  `yaml:"ports,omitempty"`
+	EnvFrom         []k8sEnvFromSource  `yaml:"envFrom,omitempty"`
+	Env             []k8sEnvVar         `yaml:"env,omitempty"`
+	VolumeMounts    []k8sVolumeMount    `yaml:"volumeMounts,omitempty"`
+	Resources       *k8sResources       `yaml:"resources,omitempty"`
+	SecurityContext *k8sSecurityContext `yaml:"securityContext,omitempty"`
+	ReadinessProbe  *k8sProbe           `yaml:"readinessProbe,omitempty"`
+	LivenessProbe   *k8sProbe           `yaml:"livenessProbe,omitempty"`
+}
+
+type k8sVolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	SubPath   string `yaml:"subPath,omitempty"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type k8sResources struct {
+	Requests map[string]string `yaml:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty"`
+}
+
+type k8sSecurityContext struct {
+	ReadOnlyRootFilesystem bool   `yaml:"readOnlyRootFilesystem,omitempty"`
+	RunAsNonRoot           bool   `yaml:"runAsNonRoot,omitempty"`
+	RunAsUser              *int64 `yaml:"runAsUser,omitempty"`
+	RunAsGroup             *int64 `yaml:"runAsGroup,omitempty"`
+}
+
+type k8sVolume struct {
+	Name                  string                                `yaml:"name"`
+	EmptyDir              *struct{}                             `yaml:"emptyDir,omitempty"`
+	HostPath              *k8sHostPathVolumeSource              `yaml:"hostPath,omitempty"`
+	PersistentVolumeClaim *k8sPersistentVolumeClaimVolumeSource `yaml:"persistentVolumeClaim,omitempty"`
+	ConfigMap             *k8sLocalObjectReference              `yaml:"configMap,omitempty"`
+	Secret                *k8sSecretVolumeSource                `yaml:"secret,omitempty"`
+}
+
+type k8sHostPathVolumeSource struct {
+	Path string `yaml:"path"`
+}
+
+type k8sPersistentVolumeClaimVolumeSource struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+type k8sSecretVolumeSource struct {
+	SecretName string `yaml:"secretName"`
+}
+
+type k8sContainerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol"`
+}
+
+type k8sEnvFromSource struct {
+	ConfigMapRef *k8sLocalObjectReference `yaml:"configMapRef,omitempty"`
+}
+
+type k8sLocalObjectReference struct {
+	Name string `yaml:"name"`
+}
+
+type k8sEnvVar struct {
+	Name      string           `yaml:"name"`
+	Value     string           `yaml:"value,omitempty"`
+	ValueFrom *k8sEnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
+type k8sEnvVarSource struct {
+	SecretKeyRef *k8sKeySelector `yaml:"secretKeyRef,omitempty"`
+}
+
+type k8sKeySelector struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type k8sProbe struct {
+	HTTPGet             *k8sHTTPGetAction `yaml:"httpGet,omitempty"`
+	InitialDelaySeconds int               `yaml:"initialDelaySeconds,omitempty"`
+	TimeoutSeconds      int               `yaml:"timeoutSeconds,omitempty"`
+	PeriodSeconds       int               `yaml:"periodSeconds,omitempty"`
+	SuccessThreshold    int               `yaml:"successThreshold,omitempty"`
+	FailureThreshold    int               `yaml:"failureThreshold,omitempty"`
+}
+
+type k8sHTTPGetAction struct {
+	Path   string `yaml:"path"`
+	Port   string `yaml:"port"`
+	Scheme string `yaml:"scheme"`
+}
@@ -0,0 +1,145 @@
+package deploy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func newTestContainer() *Container {
+	return &Container{
+		Name:      "example",
+		Kind:      "StatefulSet",
+		ImageName: "ghcr.io/octohelm/example",
+		Version:   "1.0.0",
+		Args:      []string{"serve"},
+		Ports: map[string]Port{
+			"http": {
+				Port:              8080,
+				ReadinessEndpoint: "/ready",
+				LivenessEndpoint:  "/live",
+			},
+		},
+		Env: map[string]EnvVar{
+			"EXAMPLE_LOG_LEVEL": {Value: "info"},
+			"EXAMPLE_TOKEN":     {Value: "file:///run/secrets/token", Secret: true},
+			"EXAMPLE_ADDR":      {ValueRef: `:{{ .Ports["http"].Port }}`},
+		},
+		Volumes: map[string]Volume{
+			"data": {Type: VolumeHostPath, Source: "/srv/example"},
+		},
+		Mounts: []Mount{
+			{Volume: "data", MountPath: "/var/lib/example"},
+		},
+		Resources: &Resources{
+			Requests: ResourceList{CPU: "100m", Memory: "128Mi"},
+			Limits:   ResourceList{CPU: "500m", Memory: "256Mi"},
+		},
+		SecurityContext: &SecurityContext{ReadOnlyRootFilesystem: true},
+		Labels:          map[string]string{"team": "infra"},
+		Healthcheck:     []string{"example", "probe", "http://127.0.0.1:8080/ready"},
+	}
+}
+
+func TestKubernetesObjects(t *testing.T) {
+	t.Parallel()
+
+	objects := MustValue(t, func() ([]any, error) {
+		return KubernetesObjects(newTestContainer())
+	})
+
+	workload := objects[len(objects)-1].(*k8sWorkload)
+	container := workload.Spec.Template.Spec.Containers[0]
+
+	_, tokenInConfigMap := objects[0].(*k8sConfigMap).Data["EXAMPLE_TOKEN"]
+
+	Then(
+		t, "按 kind 生成工作负载，并生成 ConfigMap、Secret 与 Service",
+		Expect(len(objects), Equal(4)),
+		Expect(objects[0].(*k8sConfigMap).Data["EXAMPLE_ADDR"], Equal(":8080")),
+		Expect(tokenInConfigMap, Equal(false)),
+		Expect(objects[1].(*k8sSecret).Metadata.Name, Equal("example")),
+		Expect(objects[1].(*k8sSecret).StringData, Equal(map[string]string{"EXAMPLE_TOKEN": "file:///run/secrets/token"})),
+		Expect(workload.Kind, Equal("StatefulSet")),
+		Expect(workload.Spec.ServiceName, Equal("example")),
+		Expect(container.Image, Equal("ghcr.io/octohelm/example:1.0.0")),
+	)
+
+	Then(
+		t, "secret 环境变量以 secretKeyRef 引用 Secret，端口探针转为 httpGet",
+		Expect(len(container.Env), Equal(1)),
+		Expect(*container.Env[0].ValueFrom.SecretKeyRef, Equal(k8sKeySelector{Name: "example", Key: "EXAMPLE_TOKEN"})),
+		Expect(container.ReadinessProbe.HTTPGet.Path, Equal("/ready")),
+		Expect(container.LivenessProbe.HTTPGet.Path, Equal("/live")),
+		Expect(container.LivenessProbe.HTTPGet.Port, Equal("http")),
+	)
+
+	Then(
+		t, "卷、资源、安全设置与标签写入工作负载",
+		Expect(workload.Spec.Template.Spec.Volumes[0].HostPath.Path, Equal("/srv/example")),
+		Expect(container.VolumeMounts[0].MountPath, Equal("/var/lib/example")),
+		Expect(container.Resources.Limits["memory"], Equal("256Mi")),
+		Expect(container.SecurityContext.ReadOnlyRootFilesystem, Equal(true)),
+		Expect(workload.Metadata.Labels["team"], Equal("infra")),
+		Expect(workload.Spec.Selector.MatchLabels, Equal(map[string]string{"app.kubernetes.io/name": "example"})),
+	)
+
+	t.Run("指定 Secret 名", func(t *testing.T) {
+		c := newTestContainer()
+		c.SecretName = "example-credentials"
+
+		objects := MustValue(t, func() ([]any, error) {
+			return KubernetesObjects(c)
+		})
+
+		container := objects[len(objects)-1].(*k8sWorkload).Spec.Template.Spec.Containers[0]
+
+		Then(
+			t, "secretKeyRef 引用指定的外部 Secret，不渲染 Secret",
+			Expect(len(objects), Equal(3)),
+			Expect(container.Env[0].ValueFrom.SecretKeyRef.Name, Equal("example-credentials")),
+		)
+	})
+
+	t.Run("未配置 secret 引用", func(t *testing.T) {
+		c := newTestContainer()
+		c.Env["EXAMPLE_TOKEN"] = EnvVar{Secret: true}
+
+		objects := MustValue(t, func() ([]any, error) {
+			return KubernetesObjects(c)
+		})
+
+		Then(
+			t, "Secret 以空值占位",
+			Expect(objects[1].(*k8sSecret).StringData, Equal(map[string]string{"EXAMPLE_TOKEN": ""})),
+		)
+	})
+}
+
+func TestRenderKubernetes(t *testing.T) {
+	t.Parallel()
+
+	c := newTestContainer()
+	c.Kind = ""
+
+	b := &bytes.Buffer{}
+	Must(t, func() error {
+		return RenderKubernetes(b, c)
+	})
+
+	Then(
+		t, "默认渲染为 Deployment 多文档 YAML",
+		Expect(strings.Count(b.String(), "\n---\n"), Equal(3)),
+		Expect(strings.Contains(b.String(), "kind: Secret\n"), Equal(true)),
+		Expect(strings.Contains(b.String(), "kind: Deployment\n"), Equal(true)),
+	)
+
+	err := RenderKubernetes(&bytes.Buffer{}, &Container{})
+
+	Then(
+		t, "缺少组件名时报错",
+		Expect(err != nil, Equal(true)),
+	)
+}
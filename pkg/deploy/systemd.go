@@ -0,0 +1,149 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RenderSystemdUnit 将 Container 渲染为 systemd service unit，环境变量通过 EnvironmentFile 加载。
+//
+// environmentFile 为空时使用 /etc/<name>/<name>.env；
+// Container.Healthcheck 非空时以 ExecStartPost 循环执行直至服务就绪，作为启动期的 healthcheck。
+func RenderSystemdUnit(w io.Writer, c *Container, environmentFile string) error {
+	if c.Name == "" {
+		return errors.New("container name is required")
+	}
+
+	if len(c.Command) == 0 {
+		return errors.New("container command is required")
+	}
+
+	if environmentFile == "" {
+		environmentFile = fmt.Sprintf("/etc/%s/%s.env", c.Name, c.Name)
+	}
+
+	b := &strings.Builder{}
+
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(b, "Description=%s\n", strings.TrimSpace(c.Name+" "+c.Version))
+	b.WriteString("Wants=network-online.target\n")
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("\n")
+
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(b, "EnvironmentFile=%s\n", environmentFile)
+
+	args := make([]string, 0, len(c.Command)+len(c.Args))
+	for _, arg := range slices.Concat(c.Command, c.Args) {
+		args = append(args, systemdQuote(systemdEscaper.Replace(arg)))
+	}
+	fmt.Fprintf(b, "ExecStart=%s\n", strings.Join(args, " "))
+
+	if len(c.Healthcheck) > 0 {
+		script := fmt.Sprintf("until %s; do sleep 1; done", shellJoin(c.Healthcheck))
+		fmt.Fprintf(b, "ExecStartPost=/bin/sh -c %s\n", systemdQuote(systemdEscaper.Replace(script)))
+		b.WriteString("TimeoutStartSec=60\n")
+	}
+
+	if sc := c.SecurityContext; sc != nil {
+		if sc.RunAsUser != nil {
+			fmt.Fprintf(b, "User=%d\n", *sc.RunAsUser)
+		}
+		if sc.RunAsGroup != nil {
+			fmt.Fprintf(b, "Group=%d\n", *sc.RunAsGroup)
+		}
+
+		if sc.ReadOnlyRootFilesystem {
+			b.WriteString("ProtectSystem=strict\n")
+
+			for _, m := range c.Mounts {
+				if !m.ReadOnly {
+					fmt.Fprintf(b, "ReadWritePaths=%s\n", m.MountPath)
+				}
+			}
+		}
+	}
+
+	if r := c.Resources; r != nil {
+		if cpu := r.Limits.CPU; cpu != "" {
+			fmt.Fprintf(b, "CPUQuota=%s\n", systemdCPUQuota(cpu))
+		}
+		if memory := r.Limits.Memory; memory != "" {
+			fmt.Fprintf(b, "MemoryMax=%s\n", systemdMemory(memory))
+		}
+		if memory := r.Requests.Memory; memory != "" {
+			fmt.Fprintf(b, "MemoryLow=%s\n", systemdMemory(memory))
+		}
+	}
+
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=5\n")
+	b.WriteString("\n")
+
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderEnvFile 将解析后的环境变量渲染为 EnvironmentFile 格式，按变量名排序。
+func RenderEnvFile(w io.Writer, c *Container) error {
+	env, err := c.ResolveEnv()
+	if err != nil {
+		return err
+	}
+
+	b := &strings.Builder{}
+
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		fmt.Fprintf(b, "%s=%s\n", name, systemdQuote(env[name]))
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// systemdCPUQuota 将 CPU 数量转为 CPUQuota 百分比，如 "500m" 转为 "50%"。
+func systemdCPUQuota(cpu string) string {
+	n, err := strconv.ParseFloat(composeCPUs(cpu), 64)
+	if err != nil {
+		return cpu
+	}
+	return strconv.FormatFloat(n*100, 'f', -1, 64) + "%"
+}
+
+// systemdMemory 将 "128Mi" 形式的内存数量转为 systemd 使用的 1024 进制单位。
+func systemdMemory(memory string) string {
+	return strings.ToUpper(composeMemory(memory))
+}
+
+// systemdEscaper 转义 Exec* 命令行中的变量展开与说明符。
+var systemdEscaper = strings.NewReplacer("$", "$$", "%", "%%")
+
+func shellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,/:=@+") == "" {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+func systemdQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\$;#") {
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
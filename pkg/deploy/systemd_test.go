@@ -0,0 +1,36 @@
+package deploy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+func TestRenderSystemdUnit(t *testing.T) {
+	t.Parallel()
+
+	c := newTestContainer()
+	c.Command = []string{"/usr/local/bin/example"}
+
+	unit := &bytes.Buffer{}
+	Must(t, func() error {
+		return RenderSystemdUnit(unit, c, "")
+	})
+
+	env := &bytes.Buffer{}
+	Must(t, func() error {
+		return RenderEnvFile(env, c)
+	})
+
+	Then(
+		t, "生成 systemd unit，环境变量经 EnvironmentFile 加载",
+		Expect(strings.Contains(unit.String(), "EnvironmentFile=/etc/example/example.env\n"), Equal(true)),
+		Expect(strings.Contains(unit.String(), "ExecStart=/usr/local/bin/example serve\n"), Equal(true)),
+		Expect(strings.Contains(unit.String(), "ProtectSystem=strict\nReadWritePaths=/var/lib/example\n"), Equal(true)),
+		Expect(strings.Contains(unit.String(), "CPUQuota=50%\nMemoryMax=256M\nMemoryLow=128M\n"), Equal(true)),
+		Expect(strings.Contains(unit.String(), "ExecStartPost=/bin/sh -c \"until example probe http://127.0.0.1:8080/ready; do sleep 1; done\"\n"), Equal(true)),
+		Expect(env.String(), Equal("EXAMPLE_ADDR=:8080\nEXAMPLE_LOG_LEVEL=info\nEXAMPLE_TOKEN=file:///run/secrets/token\n")),
+	)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 表示目标主机处于熔断状态，请求未被发送。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+func isCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
+
+// circuitBreakerRoundTripper 按主机统计连续失败次数，超过阈值后在冷却期内直接拒绝请求。
+type circuitBreakerRoundTripper struct {
+	next      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+
+	breakers sync.Map
+}
+
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	v, _ := rt.breakers.LoadOrStore(req.URL.Host, &circuitBreaker{})
+	b := v.(*circuitBreaker)
+
+	if !b.allow(time.Now(), rt.cooldown) {
+		return nil, fmt.Errorf("%s: %w", req.URL.Host, ErrCircuitOpen)
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+
+	b.done(err == nil && resp.StatusCode < http.StatusInternalServerError, time.Now(), rt.threshold)
+
+	return resp, err
+}
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// 半开状态下是否已有探测请求在途
+	probing bool
+}
+
+func (b *circuitBreaker) allow(now time.Time, cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+
+	// 冷却期过后只放行一个探测请求
+	if now.Sub(b.openedAt) >= cooldown && !b.probing {
+		b.probing = true
+		return true
+	}
+
+	return false
+}
+
+func (b *circuitBreaker) done(success bool, now time.Time, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		b.probing = false
+		return
+	}
+
+	b.failures++
+
+	if b.probing || b.failures >= threshold {
+		b.openedAt = now
+		b.probing = false
+	}
+}
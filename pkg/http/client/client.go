@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	courierclient "github.com/octohelm/courier/pkg/courierhttp/client"
+
+	"github.com/innoai-tech/infra/pkg/http/middleware"
+)
+
+// Provider 提供共享的出站 HTTP 客户端。
+// +gengo:injectable:provider
+type Provider interface {
+	// HTTPClient 返回共享的 http.Client
+	HTTPClient() *http.Client
+	// RoundTripper 返回带重试、熔断与遥测的 RoundTripper
+	RoundTripper() http.RoundTripper
+}
+
+// Client 提供带重试、熔断、传播与指标的出站 HTTP 客户端。
+// +gengo:injectable
+type Client struct {
+	// Endpoint 基础地址，未带主机的请求基于该地址解析
+	Endpoint string `flag:",omitzero" validate:"url"`
+	// TimeoutSeconds 单次请求超时（秒），含重试
+	TimeoutSeconds int `flag:",omitzero" validate:"min=0"`
+	// Proxy 代理地址，为空时读取 HTTP_PROXY 等环境变量
+	Proxy string `flag:",omitzero" validate:"url"`
+
+	// TLSCAFile 校验服务端证书的 CA 文件
+	TLSCAFile string `flag:",omitzero"`
+	// TLSCertFile 客户端证书文件
+	TLSCertFile string `flag:",omitzero"`
+	// TLSKeyFile 客户端私钥文件
+	TLSKeyFile string `flag:",omitzero"`
+	// TLSInsecureSkipVerify 跳过服务端证书校验
+	TLSInsecureSkipVerify bool `flag:",omitzero"`
+
+	// RetryMax 幂等请求的最大重试次数，小于 0 时关闭重试
+	RetryMax int `flag:",omitzero"`
+	// RetryBackoffMilliseconds 首次重试的退避时间（毫秒），之后逐次翻倍
+	RetryBackoffMilliseconds int `flag:",omitzero" validate:"min=0"`
+	// RetryBackoffMaxMilliseconds 重试退避时间上限（毫秒）
+	RetryBackoffMaxMilliseconds int `flag:",omitzero" validate:"min=0"`
+
+	// CircuitBreakerThreshold 同一主机连续失败多少次后熔断，小于 0 时关闭熔断
+	CircuitBreakerThreshold int `flag:",omitzero"`
+	// CircuitBreakerCooldownSeconds 熔断后放行探测请求前的等待时间（秒）
+	CircuitBreakerCooldownSeconds int `flag:",omitzero" validate:"min=0"`
+
+	endpoint     *url.URL
+	roundTripper http.RoundTripper
+	httpClient   *http.Client
+}
+
+var _ Provider = &Client{}
+
+// SetDefaults 补齐超时、重试与熔断的默认值。
+func (c *Client) SetDefaults() {
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = 30
+	}
+
+	if c.RetryMax == 0 {
+		c.RetryMax = 3
+	}
+
+	if c.RetryBackoffMilliseconds == 0 {
+		c.RetryBackoffMilliseconds = 100
+	}
+
+	if c.RetryBackoffMaxMilliseconds == 0 {
+		c.RetryBackoffMaxMilliseconds = 2000
+	}
+
+	if c.CircuitBreakerThreshold == 0 {
+		c.CircuitBreakerThreshold = 5
+	}
+
+	if c.CircuitBreakerCooldownSeconds == 0 {
+		c.CircuitBreakerCooldownSeconds = 30
+	}
+}
+
+// InjectContext 注入 Provider，并让 courier 客户端默认使用同一 RoundTripper。
+func (c *Client) InjectContext(ctx context.Context) context.Context {
+	ctx = ProviderInjectContext(ctx, c)
+
+	return courierclient.ContextWithRoundTripperCreator(ctx, func() http.RoundTripper {
+		return c.RoundTripper()
+	})
+}
+
+// HTTPClient 返回共享的 http.Client。
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// RoundTripper 返回带重试、熔断与遥测的 RoundTripper。
+func (c *Client) RoundTripper() http.RoundTripper {
+	return c.roundTripper
+}
+
+// Do 发送请求；未带主机的请求 URL 基于 Endpoint 解析。
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) afterInit(ctx context.Context) error {
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint: %w", err)
+		}
+		c.endpoint = u
+	}
+
+	transport, err := c.newTransport()
+	if err != nil {
+		return err
+	}
+
+	var rt http.RoundTripper = transport
+
+	// 由内到外：遥测记录每次尝试，熔断统计每次尝试的结果，重试包裹整个过程
+	rt = middleware.NewLogRoundTripper()(rt)
+
+	if c.CircuitBreakerThreshold > 0 {
+		rt = &circuitBreakerRoundTripper{
+			next:      rt,
+			threshold: c.CircuitBreakerThreshold,
+			cooldown:  time.Duration(c.CircuitBreakerCooldownSeconds) * time.Second,
+		}
+	}
+
+	if c.RetryMax > 0 {
+		rt = &retryRoundTripper{
+			next:       rt,
+			max:        c.RetryMax,
+			backoff:    time.Duration(c.RetryBackoffMilliseconds) * time.Millisecond,
+			backoffMax: time.Duration(c.RetryBackoffMaxMilliseconds) * time.Millisecond,
+		}
+	}
+
+	if c.endpoint != nil {
+		rt = &endpointRoundTripper{next: rt, endpoint: c.endpoint}
+	}
+
+	c.roundTripper = rt
+	c.httpClient = &http.Client{
+		Transport: rt,
+		Timeout:   time.Duration(c.TimeoutSeconds) * time.Second,
+	}
+
+	return nil
+}
+
+func (c *Client) newTransport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	t.Proxy = http.ProxyFromEnvironment
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	t.DialContext = (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	t.TLSClientConfig = tlsConfig
+
+	return t, nil
+}
+
+func (c *Client) tlsConfig() (*tls.Config, error) {
+	conf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// nolint:gosec
+		InsecureSkipVerify: c.TLSInsecureSkipVerify,
+	}
+
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca file failed: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", c.TLSCAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls client certificate failed: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+// endpointRoundTripper 将未带主机的请求基于 Endpoint 解析为完整地址。
+type endpointRoundTripper struct {
+	next     http.RoundTripper
+	endpoint *url.URL
+}
+
+func (rt *endpointRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "" {
+		return rt.next.RoundTrip(req)
+	}
+
+	r := req.Clone(req.Context())
+	r.URL = rt.endpoint.ResolveReference(req.URL)
+	r.Host = ""
+
+	return rt.next.RoundTrip(r)
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/octohelm/x/testing/v2"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestClientRetryIdempotentRequest(t *testing.T) {
+	t.Parallel()
+
+	calls := atomic.Int32{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if calls.Add(1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := &Client{Endpoint: srv.URL, RetryBackoffMilliseconds: 1}
+	c.SetDefaults()
+
+	Must(t, func() error {
+		return c.Init(context.Background())
+	})
+
+	req := MustValue(t, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, "/ping", nil)
+	})
+
+	resp := MustValue(t, func() (*http.Response, error) {
+		return c.Do(req)
+	})
+	_ = resp.Body.Close()
+
+	Then(
+		t, "幂等请求基于 Endpoint 解析地址，并在 503 时重试直至成功",
+		Expect(resp.StatusCode, Equal(http.StatusOK)),
+		Expect(calls.Load(), Equal(int32(3))),
+	)
+}
+
+func TestRetrySkipsNonIdempotentRequest(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	rt := &retryRoundTripper{
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}),
+		max:        3,
+		backoff:    time.Millisecond,
+		backoffMax: time.Millisecond,
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/", nil)
+
+	resp := MustValue(t, func() (*http.Response, error) {
+		return rt.RoundTrip(req)
+	})
+
+	Then(
+		t, "非幂等请求不重试",
+		Expect(resp.StatusCode, Equal(http.StatusServiceUnavailable)),
+		Expect(calls, Equal(1)),
+	)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	failed := errors.New("connection refused")
+
+	rt := &circuitBreakerRoundTripper{
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, failed
+		}),
+		threshold: 2,
+		cooldown:  time.Hour,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+	_, _ = rt.RoundTrip(req)
+	_, _ = rt.RoundTrip(req)
+	_, err := rt.RoundTrip(req)
+
+	Then(
+		t, "连续失败达到阈值后熔断，不再发送请求",
+		Expect(errors.Is(err, ErrCircuitOpen), Equal(true)),
+		Expect(calls, Equal(2)),
+	)
+}
@@ -0,0 +1,14 @@
+// Package client 提供可接入 configuration 生命周期的出站 HTTP 客户端。
+//
+// 它负责：
+//   - 通过 flag 配置基础地址、超时、TLS 与代理
+//   - 为幂等请求提供带退避的重试，并按目标主机熔断
+//   - 注入 trace 传播头，记录 `http.client.*` 指标与请求日志
+//   - 将 RoundTripper 注入上下文，使 courier 生成的客户端自动使用
+//
+// 它不负责：
+//   - 定义具体的 API 客户端与请求结构
+//   - 替代业务层的错误处理与降级策略
+//
+// +gengo:runtimedoc
+package client
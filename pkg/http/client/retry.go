@@ -0,0 +1,102 @@
+package client
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+var idempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// retryRoundTripper 对幂等请求在网络错误或可重试的状态码时按指数退避重试。
+type retryRoundTripper struct {
+	next       http.RoundTripper
+	max        int
+	backoff    time.Duration
+	backoffMax time.Duration
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !canRetry(req) {
+		return rt.next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := rt.next.RoundTrip(r)
+
+		if attempt >= rt.max || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// 读尽响应体以复用连接
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(rt.wait(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// wait 返回第 attempt 次重试前的等待时间，带 ±20% 抖动。
+func (rt *retryRoundTripper) wait(attempt int) time.Duration {
+	d := rt.backoff << attempt
+	if d <= 0 || d > rt.backoffMax {
+		d = rt.backoffMax
+	}
+
+	jitter := time.Duration(rand.Int64N(int64(d)/5 + 1))
+	if rand.IntN(2) == 0 {
+		return d - jitter
+	}
+	return d + jitter
+}
+
+func canRetry(req *http.Request) bool {
+	if !slices.Contains(idempotentMethods, req.Method) {
+		return false
+	}
+	// 请求体无法重放时不重试
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !isCircuitOpen(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
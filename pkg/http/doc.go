@@ -2,7 +2,9 @@
 //
 // 它负责：
 //   - 将 courier router 组装成可运行的 HTTP server
-//   - 统一接入 context injector、压缩、日志、指标、pprof 与健康检查中间件
+//   - 统一接入 context injector、压缩、日志、指标、pprof、日志级别控制与健康检查中间件
+//   - 按配置接入 Bearer token、JWT、Basic 与 mTLS 客户端证书认证，认证作用于包括 `/.sys/` 内置接口在内的全部请求
+//   - 从证书文件提供 TLS，并在文件变更或触发重载（如 `configuration.ReloadOnHangup()` 下的 SIGHUP）时热加载
 //   - 暴露服务地址、TLS provider 与 router/global handler 的装配入口
 //
 // 它不负责：
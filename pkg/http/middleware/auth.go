@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	contextx "github.com/octohelm/x/context"
+)
+
+// Principal 为认证后的请求主体。
+type Principal struct {
+	// Subject 主体标识，记录为访问日志与 span 的 enduser.id
+	Subject string
+	// Method 认证方式，如 bearer、jwt、mtls、basic
+	Method string
+	// Claims 认证方式附带的声明，如 JWT 的 payload
+	Claims map[string]any
+}
+
+var principalContext = contextx.New[*Principal]()
+
+// ContextWithPrincipal 返回注入了认证主体的上下文。
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return principalContext.Inject(ctx, p)
+}
+
+// PrincipalFromContext 从上下文中读取认证主体。
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := principalContext.MayFrom(ctx)
+	return p, ok && p != nil
+}
+
+// PrincipalSubject 返回上下文中认证主体的标识，未认证时返回空字符串，可用于 RateLimitBySubject。
+func PrincipalSubject(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// ErrUnauthenticated 表示请求未携带任何可识别的凭证。
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator 为可插拔的请求认证方式。
+type Authenticator interface {
+	// Authenticate 返回请求的认证主体；请求未携带该方式的凭证时返回 nil, nil，凭证无效时返回错误
+	Authenticate(req *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc 将函数适配为 Authenticator。
+type AuthenticatorFunc func(req *http.Request) (*Principal, error)
+
+// Authenticate 调用函数本身。
+func (fn AuthenticatorFunc) Authenticate(req *http.Request) (*Principal, error) {
+	return fn(req)
+}
+
+// Authenticators 组合多个 Authenticator，按顺序返回第一个识别出凭证的结果。
+func Authenticators(authenticators ...Authenticator) Authenticator {
+	return authenticatorChain(authenticators)
+}
+
+type authenticatorChain []Authenticator
+
+func (chain authenticatorChain) Authenticate(req *http.Request) (*Principal, error) {
+	for _, a := range chain {
+		p, err := a.Authenticate(req)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (chain authenticatorChain) challenges() []string {
+	var challenges []string
+	for _, a := range chain {
+		if c, ok := a.(challenger); ok {
+			for _, challenge := range c.challenges() {
+				if !slices.Contains(challenges, challenge) {
+					challenges = append(challenges, challenge)
+				}
+			}
+		}
+	}
+	return challenges
+}
+
+// challenger 为可提供 WWW-Authenticate 质询的 Authenticator。
+type challenger interface {
+	challenges() []string
+}
+
+// principalRecorder 为记录认证主体的响应写入器，用于访问日志。
+type principalRecorder interface {
+	recordPrincipal(p *Principal)
+}
+
+// AuthHandler 创建认证中间件，可作为全局中间件或紧随 LogAndMetricHandler 的路由中间件使用。
+//
+// 认证通过的主体注入请求上下文，并记录到访问日志与 span 的 enduser.id；
+// 未认证或凭证无效时返回 401。publicPaths 中的路径无需认证，以 * 结尾时按前缀匹配，如 /.sys/*。
+func AuthHandler(authenticator Authenticator, publicPaths ...string) func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return &authHandler{
+			authenticator: authenticator,
+			publicPaths:   publicPaths,
+			nextHandler:   handler,
+		}
+	}
+}
+
+type authHandler struct {
+	authenticator Authenticator
+	publicPaths   []string
+	nextHandler   http.Handler
+}
+
+func (h *authHandler) isPublic(path string) bool {
+	for _, p := range h.publicPaths {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *authHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if h.authenticator == nil || h.isPublic(req.URL.Path) {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	ctx := req.Context()
+
+	p, err := h.authenticator.Authenticate(req)
+	if err == nil && p == nil {
+		err = ErrUnauthenticated
+	}
+
+	if err != nil {
+		challenges := []string{"Bearer"}
+		if c, ok := h.authenticator.(challenger); ok && len(c.challenges()) > 0 {
+			challenges = c.challenges()
+		}
+		for _, challenge := range challenges {
+			rw.Header().Add("WWW-Authenticate", challenge)
+		}
+
+		writeStatusError(rw, http.StatusUnauthorized, "Unauthorized", err.Error())
+		return
+	}
+
+	if r, ok := rw.(principalRecorder); ok {
+		r.recordPrincipal(p)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Key("enduser.id").String(p.Subject))
+
+	h.nextHandler.ServeHTTP(rw, req.WithContext(ContextWithPrincipal(ctx, p)))
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LoadHtpasswdFile 从本地 htpasswd 文件读取用户名到密码哈希的映射。
+//
+// 支持 bcrypt（$2y$）、Apache MD5（$apr1$）与 SHA1（{SHA}）哈希，忽略空行与 # 开头的注释。
+func LoadHtpasswdFile(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	users := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		username, hash, ok := strings.Cut(line, ":")
+		if !ok || username == "" || !supportedHtpasswdHash(hash) {
+			return nil, fmt.Errorf("invalid htpasswd %s:%d", filename, i)
+		}
+
+		users[username] = hash
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func supportedHtpasswdHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2") || strings.HasPrefix(hash, "$apr1$") || strings.HasPrefix(hash, "{SHA}")
+}
+
+// NewBasicAuthenticator 创建 Basic 认证，users 为用户名到 htpasswd 密码哈希的映射。
+func NewBasicAuthenticator(realm string, users map[string]string) Authenticator {
+	return &basicAuthenticator{realm: realm, users: users}
+}
+
+type basicAuthenticator struct {
+	realm string
+	users map[string]string
+}
+
+func (a *basicAuthenticator) Authenticate(req *http.Request) (*Principal, error) {
+	username, password, ok := req.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+
+	hash, ok := a.users[username]
+	if !ok || !verifyHtpasswd(hash, password) {
+		return nil, errors.New("invalid username or password")
+	}
+
+	return &Principal{Subject: username, Method: "basic"}, nil
+}
+
+func (a *basicAuthenticator) challenges() []string {
+	return []string{"Basic realm=" + strconv.Quote(a.realm)}
+}
+
+func verifyHtpasswd(hash string, password string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$2"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, "$apr1$"):
+		salt, _, _ := strings.Cut(strings.TrimPrefix(hash, "$apr1$"), "$")
+		return subtle.ConstantTimeCompare([]byte(apr1(password, salt)), []byte(hash)) == 1
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		return subtle.ConstantTimeCompare([]byte("{SHA}"+base64.StdEncoding.EncodeToString(sum[:])), []byte(hash)) == 1
+	default:
+		return false
+	}
+}
+
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1 按 Apache 的 MD5 变体计算 htpasswd 哈希。
+func apr1(password string, salt string) string {
+	const magic = "$apr1$"
+
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	alternate := md5.Sum([]byte(password + salt + password))
+
+	h := md5.New()
+	h.Write([]byte(password + magic + salt))
+
+	for n := len(password); n > 0; n -= 16 {
+		h.Write(alternate[:min(n, 16)])
+	}
+
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			h.Write([]byte{0})
+		} else {
+			h.Write([]byte{password[0]})
+		}
+	}
+
+	sum := h.Sum(nil)
+
+	for i := range 1000 {
+		h := md5.New()
+
+		if i&1 == 1 {
+			h.Write([]byte(password))
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			h.Write([]byte(password))
+		}
+		if i&1 == 1 {
+			h.Write(sum)
+		} else {
+			h.Write([]byte(password))
+		}
+
+		sum = h.Sum(nil)
+	}
+
+	b := strings.Builder{}
+	b.WriteString(magic + salt + "$")
+
+	encode := func(v uint32, n int) {
+		for range n {
+			b.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+
+	for _, i := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		encode(uint32(sum[i[0]])<<16|uint32(sum[i[1]])<<8|uint32(sum[i[2]]), 4)
+	}
+	encode(uint32(sum[11]), 2)
+
+	return b.String()
+}
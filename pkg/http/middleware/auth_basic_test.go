@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+// htpasswd 为 Apache 文档中 myPassword 的各类哈希。
+const htpasswd = `# users
+bcrypt:$2y$05$c4WoMPo3SXsafkva.HHa6uXQZWr7oboPiC2bT/r7q1BB8I2s0BRqC
+apr1:$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/
+
+sha:{SHA}VBPuJHI7uixaa6LQGWx4s+5GKNE=
+`
+
+func TestBasicAuthenticator(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "htpasswd")
+
+	testingv2.Must(t, func() error {
+		return os.WriteFile(filename, []byte(htpasswd), 0o600)
+	})
+
+	users := testingv2.MustValue(t, func() (map[string]string, error) {
+		return LoadHtpasswdFile(filename)
+	})
+
+	a := NewBasicAuthenticator("demo", users)
+
+	authenticate := func(username string, password string) (*Principal, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(username, password)
+		return a.Authenticate(req)
+	}
+
+	t.Run("校验 bcrypt、apr1 与 SHA1 哈希", func(t *testing.T) {
+		for _, username := range []string{"bcrypt", "apr1", "sha"} {
+			p := testingv2.MustValue(t, func() (*Principal, error) {
+				return authenticate(username, "myPassword")
+			})
+
+			testingv2.Then(t, username+" 用户认证通过",
+				testingv2.Expect(p.Subject, testingv2.Equal(username)),
+				testingv2.Expect(p.Method, testingv2.Equal("basic")),
+			)
+		}
+	})
+
+	t.Run("密码错误或用户不存在", func(t *testing.T) {
+		_, wrongPassword := authenticate("apr1", "password")
+		_, unknownUser := authenticate("nobody", "myPassword")
+
+		testingv2.Then(t, "返回错误",
+			testingv2.Expect(wrongPassword != nil, testingv2.Equal(true)),
+			testingv2.Expect(unknownUser != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("未携带 Basic 凭证", func(t *testing.T) {
+		p, err := a.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+
+		testingv2.Then(t, "视为未携带该方式的凭证",
+			testingv2.Expect(p == nil && err == nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("不支持的哈希", func(t *testing.T) {
+		testingv2.Must(t, func() error {
+			return os.WriteFile(filename, []byte("crypt:rqXexS6ZhobKA\n"), 0o600)
+		})
+
+		_, err := LoadHtpasswdFile(filename)
+
+		testingv2.Then(t, "读取失败",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+}
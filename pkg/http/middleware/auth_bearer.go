@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// bearerToken 返回 Authorization 请求头中的 Bearer token。
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// NewBearerTokenAuthenticator 创建静态 Bearer token 认证，tokens 为 token 到主体标识的映射。
+//
+// 未命中的 token 视为未携带该方式的凭证，以便与 JWT 认证组合使用。
+func NewBearerTokenAuthenticator(tokens map[string]string) Authenticator {
+	return &bearerTokenAuthenticator{tokens: tokens}
+}
+
+// ParseBearerTokens 解析逗号分隔的 subject:token 列表，省略 subject 时以 token 序号命名，如 token-0。
+func ParseBearerTokens(s string) (map[string]string, error) {
+	tokens := map[string]string{}
+
+	for i, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		subject, token, ok := strings.Cut(item, ":")
+		if !ok {
+			subject, token = "token-"+strconv.Itoa(i), item
+		}
+
+		if token == "" {
+			return nil, errors.New("empty bearer token for " + subject)
+		}
+
+		tokens[token] = subject
+	}
+
+	return tokens, nil
+}
+
+type bearerTokenAuthenticator struct {
+	tokens map[string]string
+}
+
+func (a *bearerTokenAuthenticator) Authenticate(req *http.Request) (*Principal, error) {
+	token, ok := bearerToken(req)
+	if !ok {
+		return nil, nil
+	}
+
+	// 常量时间比较，避免通过响应耗时猜测 token
+	for t, subject := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return &Principal{Subject: subject, Method: "bearer"}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (a *bearerTokenAuthenticator) challenges() []string {
+	return []string{"Bearer"}
+}
@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// JWK 为 JWKS 中的单个密钥，支持 oct（HMAC）与 RSA。
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitzero"`
+	Alg string `json:"alg,omitzero"`
+	// K 为 oct 密钥，base64url 编码
+	K string `json:"k,omitzero"`
+	// N 与 E 为 RSA 公钥，base64url 编码
+	N string `json:"n,omitzero"`
+	E string `json:"e,omitzero"`
+}
+
+// JWKS 为 JSON Web Key Set。
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadJWKSFile 从本地文件读取 JWKS。
+func LoadJWKSFile(filename string) (*JWKS, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := &JWKS{}
+	if err := json.Unmarshal(data, jwks); err != nil {
+		return nil, fmt.Errorf("invalid jwks %s: %w", filename, err)
+	}
+
+	return jwks, nil
+}
+
+// JWTOption 表示用于配置 JWT 认证的函数选项。
+type JWTOption func(a *jwtAuthenticator)
+
+// JWTAudience 要求 aud 声明（字符串或字符串数组）包含 audience，为空时不校验。
+func JWTAudience(audience string) JWTOption {
+	return func(a *jwtAuthenticator) {
+		a.audience = audience
+	}
+}
+
+// JWTIssuer 要求 iss 声明与 issuer 一致，为空时不校验。
+func JWTIssuer(issuer string) JWTOption {
+	return func(a *jwtAuthenticator) {
+		a.issuer = issuer
+	}
+}
+
+// NewJWTAuthenticator 创建 JWT 认证，使用 jwks 校验 HS256/384/512 与 RS256/384/512 签名及 exp、nbf，
+// 并按选项校验 aud 与 iss。
+//
+// 主体标识取自 sub 声明；Bearer token 不是 JWT 格式时视为未携带该方式的凭证。
+func NewJWTAuthenticator(jwks *JWKS, opts ...JWTOption) (Authenticator, error) {
+	a := &jwtAuthenticator{}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	for _, k := range jwks.Keys {
+		key, err := parseJWK(k)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = append(a.keys, key)
+	}
+
+	if len(a.keys) == 0 {
+		return nil, errors.New("jwks without keys")
+	}
+
+	return a, nil
+}
+
+type jwtKey struct {
+	kid string
+	alg string
+	// secret 为 HMAC 密钥
+	secret []byte
+	// publicKey 为 RSA 公钥
+	publicKey *rsa.PublicKey
+}
+
+func parseJWK(k JWK) (*jwtKey, error) {
+	key := &jwtKey{kid: k.Kid, alg: k.Alg}
+
+	switch k.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.K, "="))
+		if err != nil || len(secret) == 0 {
+			return nil, fmt.Errorf("invalid oct jwk %q", k.Kid)
+		}
+		key.secret = secret
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+		if err != nil || len(n) == 0 {
+			return nil, fmt.Errorf("invalid rsa jwk %q", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+		if err != nil || len(e) == 0 {
+			return nil, fmt.Errorf("invalid rsa jwk %q", k.Kid)
+		}
+		key.publicKey = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwk kty %q", k.Kty)
+	}
+
+	return key, nil
+}
+
+type jwtAuthenticator struct {
+	keys     []*jwtKey
+	audience string
+	issuer   string
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (a *jwtAuthenticator) Authenticate(req *http.Request) (*Principal, error) {
+	token, ok := bearerToken(req)
+	if !ok || strings.Count(token, ".") != 2 {
+		return nil, nil
+	}
+
+	parts := strings.Split(token, ".")
+
+	header := &jwtHeader{}
+	if err := decodeJWTPart(parts[0], header); err != nil {
+		return nil, fmt.Errorf("invalid jwt header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt signature: %w", err)
+	}
+
+	if err := a.verify(header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]any{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid jwt claims: %w", err)
+	}
+
+	now := time.Now()
+
+	if exp, ok := claims["exp"].(float64); ok && !now.Before(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("jwt expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("jwt not valid yet")
+	}
+
+	if a.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.issuer {
+			return nil, errors.New("jwt issuer mismatch")
+		}
+	}
+	if a.audience != "" && !jwtAudienceContains(claims["aud"], a.audience) {
+		return nil, errors.New("jwt audience mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("jwt without sub")
+	}
+
+	return &Principal{Subject: subject, Method: "jwt", Claims: claims}, nil
+}
+
+// jwtAudienceContains 判断 aud 声明是否包含 audience，aud 可为字符串或字符串数组。
+func jwtAudienceContains(aud any, audience string) bool {
+	switch x := aud.(type) {
+	case string:
+		return x == audience
+	case []any:
+		for _, v := range x {
+			if s, ok := v.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *jwtAuthenticator) verify(header *jwtHeader, signingInput []byte, signature []byte) error {
+	hash, err := jwtHash(header.Alg)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range a.keys {
+		if header.Kid != "" && key.kid != "" && header.Kid != key.kid {
+			continue
+		}
+		if key.alg != "" && key.alg != header.Alg {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(header.Alg, "HS") && key.secret != nil:
+			mac := hmac.New(hash.New, key.secret)
+			mac.Write(signingInput)
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return nil
+			}
+		case strings.HasPrefix(header.Alg, "RS") && key.publicKey != nil:
+			h := hash.New()
+			h.Write(signingInput)
+			if rsa.VerifyPKCS1v15(key.publicKey, hash, h.Sum(nil), signature) == nil {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("invalid jwt signature")
+}
+
+func jwtHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "HS256", "RS256":
+		return crypto.SHA256, nil
+	case "HS384", "RS384":
+		return crypto.SHA384, nil
+	case "HS512", "RS512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported jwt alg %q", alg)
+	}
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (a *jwtAuthenticator) challenges() []string {
+	return []string{"Bearer"}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+var jwtSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signJWT 以 sign 对 header.claims 签名并返回 JWT。
+func signJWT(t *testing.T, header map[string]any, claims map[string]any, sign func(input []byte) []byte) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data := testingv2.MustValue(t, func() ([]byte, error) {
+			return json.Marshal(v)
+		})
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	input := encode(header) + "." + encode(claims)
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func hs256(secret []byte) func(input []byte) []byte {
+	return func(input []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func(input []byte) []byte {
+	return func(input []byte) []byte {
+		sum := sha256.Sum256(input)
+		return testingv2.MustValue(t, func() ([]byte, error) {
+			return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+		})
+	}
+}
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey := testingv2.MustValue(t, func() (*rsa.PrivateKey, error) {
+		return rsa.GenerateKey(rand.Reader, 2048)
+	})
+
+	a := testingv2.MustValue(t, func() (Authenticator, error) {
+		return NewJWTAuthenticator(&JWKS{
+			Keys: []JWK{
+				{Kty: "oct", Kid: "hmac", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(jwtSecret)},
+				{
+					Kty: "RSA",
+					Kid: "rsa",
+					Alg: "RS256",
+					N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	now := time.Now()
+	claims := map[string]any{"sub": "alice", "exp": now.Add(time.Hour).Unix()}
+
+	t.Run("HS256 与 RS256 签名", func(t *testing.T) {
+		hs := testingv2.MustValue(t, func() (*Principal, error) {
+			return a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "HS256", "kid": "hmac"}, claims, hs256(jwtSecret))))
+		})
+
+		rs := testingv2.MustValue(t, func() (*Principal, error) {
+			return a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims, rs256(t, rsaKey))))
+		})
+
+		testingv2.Then(t, "主体取自 sub 声明",
+			testingv2.Expect(hs.Subject, testingv2.Equal("alice")),
+			testingv2.Expect(hs.Method, testingv2.Equal("jwt")),
+			testingv2.Expect(rs.Subject, testingv2.Equal("alice")),
+		)
+	})
+
+	t.Run("签名错误", func(t *testing.T) {
+		_, err := a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "HS256", "kid": "hmac"}, claims, hs256([]byte("other")))))
+
+		testingv2.Then(t, "返回错误",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("alg 或 kid 与密钥不匹配", func(t *testing.T) {
+		_, kidMismatch := a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "HS256", "kid": "rsa"}, claims, hs256(jwtSecret))))
+		_, algMismatch := a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "HS512", "kid": "hmac"}, claims, hs256(jwtSecret))))
+		_, unsupported := a.Authenticate(bearerRequest(signJWT(t, map[string]any{"alg": "none"}, claims, func(input []byte) []byte {
+			return nil
+		})))
+
+		testingv2.Then(t, "不使用不匹配的密钥校验，不支持的 alg 返回错误",
+			testingv2.Expect(kidMismatch != nil, testingv2.Equal(true)),
+			testingv2.Expect(algMismatch != nil, testingv2.Equal(true)),
+			testingv2.Expect(unsupported != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("exp 与 nbf", func(t *testing.T) {
+		header := map[string]any{"alg": "HS256", "kid": "hmac"}
+
+		_, expired := a.Authenticate(bearerRequest(signJWT(t, header, map[string]any{"sub": "alice", "exp": now.Add(-time.Minute).Unix()}, hs256(jwtSecret))))
+		_, notYetValid := a.Authenticate(bearerRequest(signJWT(t, header, map[string]any{"sub": "alice", "nbf": now.Add(time.Hour).Unix()}, hs256(jwtSecret))))
+		valid := testingv2.MustValue(t, func() (*Principal, error) {
+			return a.Authenticate(bearerRequest(signJWT(t, header, map[string]any{"sub": "alice", "nbf": now.Add(-time.Minute).Unix()}, hs256(jwtSecret))))
+		})
+
+		testingv2.Then(t, "过期或未生效的 JWT 被拒绝",
+			testingv2.Expect(expired != nil, testingv2.Equal(true)),
+			testingv2.Expect(notYetValid != nil, testingv2.Equal(true)),
+			testingv2.Expect(valid.Subject, testingv2.Equal("alice")),
+		)
+	})
+
+	t.Run("aud 与 iss", func(t *testing.T) {
+		strict := testingv2.MustValue(t, func() (Authenticator, error) {
+			return NewJWTAuthenticator(&JWKS{
+				Keys: []JWK{
+					{Kty: "oct", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(jwtSecret)},
+				},
+			}, JWTAudience("api"), JWTIssuer("https://issuer.example.com"))
+		})
+
+		header := map[string]any{"alg": "HS256"}
+		sign := func(claims map[string]any) *http.Request {
+			claims["sub"] = "alice"
+			claims["exp"] = now.Add(time.Hour).Unix()
+			return bearerRequest(signJWT(t, header, claims, hs256(jwtSecret)))
+		}
+
+		single := testingv2.MustValue(t, func() (*Principal, error) {
+			return strict.Authenticate(sign(map[string]any{"aud": "api", "iss": "https://issuer.example.com"}))
+		})
+		multiple := testingv2.MustValue(t, func() (*Principal, error) {
+			return strict.Authenticate(sign(map[string]any{"aud": []string{"web", "api"}, "iss": "https://issuer.example.com"}))
+		})
+		_, wrongAudience := strict.Authenticate(sign(map[string]any{"aud": "web", "iss": "https://issuer.example.com"}))
+		_, missingAudience := strict.Authenticate(sign(map[string]any{"iss": "https://issuer.example.com"}))
+		_, wrongIssuer := strict.Authenticate(sign(map[string]any{"aud": "api", "iss": "https://other.example.com"}))
+
+		testingv2.Then(t, "aud 包含指定受众且 iss 一致时通过，否则被拒绝",
+			testingv2.Expect(single.Subject, testingv2.Equal("alice")),
+			testingv2.Expect(multiple.Subject, testingv2.Equal("alice")),
+			testingv2.Expect(wrongAudience != nil, testingv2.Equal(true)),
+			testingv2.Expect(missingAudience != nil, testingv2.Equal(true)),
+			testingv2.Expect(wrongIssuer != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("非 JWT 格式的 Bearer token", func(t *testing.T) {
+		p, err := a.Authenticate(bearerRequest("static-token"))
+
+		testingv2.Then(t, "视为未携带该方式的凭证",
+			testingv2.Expect(p == nil && err == nil, testingv2.Equal(true)),
+		)
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// NewClientCertAuthenticator 创建 mTLS 客户端证书认证，需 TLS 配置校验客户端证书。
+//
+// 主体标识取自已校验证书的 CommonName，为空时取第一个 URI 或 DNS SAN；未携带已校验证书时视为未携带该方式的凭证。
+func NewClientCertAuthenticator() Authenticator {
+	return &clientCertAuthenticator{}
+}
+
+type clientCertAuthenticator struct{}
+
+func (a *clientCertAuthenticator) Authenticate(req *http.Request) (*Principal, error) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+
+	cert := req.TLS.VerifiedChains[0][0]
+
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.URIs) > 0 {
+		subject = cert.URIs[0].String()
+	}
+	if subject == "" && len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+	}
+	if subject == "" {
+		return nil, nil
+	}
+
+	return &Principal{
+		Subject: subject,
+		Method:  "mtls",
+		Claims: map[string]any{
+			"issuer":        cert.Issuer.String(),
+			"serial_number": cert.SerialNumber.String(),
+		},
+	}, nil
+}
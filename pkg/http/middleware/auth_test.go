@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestAuthHandler(t *testing.T) {
+	t.Run("公开路径匹配", func(t *testing.T) {
+		h := &authHandler{publicPaths: []string{"/healthz", "/.sys/*"}}
+
+		testingv2.Then(t, "以 * 结尾时按前缀匹配，否则精确匹配",
+			testingv2.Expect(h.isPublic("/healthz"), testingv2.Equal(true)),
+			testingv2.Expect(h.isPublic("/healthz/detail"), testingv2.Equal(false)),
+			testingv2.Expect(h.isPublic("/.sys/liveness"), testingv2.Equal(true)),
+			testingv2.Expect(h.isPublic("/.sys/"), testingv2.Equal(true)),
+			testingv2.Expect(h.isPublic("/.system"), testingv2.Equal(false)),
+			testingv2.Expect(h.isPublic("/api/orgs"), testingv2.Equal(false)),
+		)
+	})
+
+	bearer := NewBearerTokenAuthenticator(map[string]string{"static-token": "ci"})
+	jwt := testingv2.MustValue(t, func() (Authenticator, error) {
+		return NewJWTAuthenticator(&JWKS{
+			Keys: []JWK{
+				{Kty: "oct", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(jwtSecret)},
+			},
+		})
+	})
+	basic := NewBasicAuthenticator("demo", map[string]string{})
+
+	serve := func(authenticator Authenticator, req *http.Request) (*httptest.ResponseRecorder, string) {
+		subject := ""
+
+		h := AuthHandler(authenticator, "/.sys/*")(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			subject = PrincipalSubject(req.Context())
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw, subject
+	}
+
+	t.Run("Bearer 与 JWT 同时配置", func(t *testing.T) {
+		chain := Authenticators(bearer, jwt)
+
+		token := signJWT(t, map[string]any{"alg": "HS256"}, map[string]any{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}, hs256(jwtSecret))
+
+		static, staticSubject := serve(chain, bearerRequest("static-token"))
+		signed, signedSubject := serve(chain, bearerRequest(token))
+		invalid, _ := serve(chain, bearerRequest(token+"x"))
+
+		testingv2.Then(t, "静态 token 由 Bearer 认证识别，JWT 交由 JWT 认证校验",
+			testingv2.Expect(static.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(staticSubject, testingv2.Equal("ci")),
+			testingv2.Expect(signed.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(signedSubject, testingv2.Equal("alice")),
+			testingv2.Expect(invalid.Code, testingv2.Equal(http.StatusUnauthorized)),
+		)
+	})
+
+	t.Run("未认证", func(t *testing.T) {
+		anonymous, _ := serve(Authenticators(bearer, jwt, basic), httptest.NewRequest(http.MethodGet, "/api/orgs", nil))
+		unknown, _ := serve(Authenticators(bearer, jwt), bearerRequest("unknown-token"))
+
+		testingv2.Then(t, "返回 401 与去重后的 WWW-Authenticate 质询",
+			testingv2.Expect(anonymous.Code, testingv2.Equal(http.StatusUnauthorized)),
+			testingv2.Expect(anonymous.Header().Values("WWW-Authenticate"), testingv2.Equal([]string{"Bearer", `Basic realm="demo"`})),
+			testingv2.Expect(strings.Contains(anonymous.Body.String(), `"key":"Unauthorized"`), testingv2.Equal(true)),
+			testingv2.Expect(unknown.Code, testingv2.Equal(http.StatusUnauthorized)),
+			testingv2.Expect(unknown.Header().Values("WWW-Authenticate"), testingv2.Equal([]string{"Bearer"})),
+		)
+	})
+
+	t.Run("公开路径无需认证", func(t *testing.T) {
+		rw, subject := serve(bearer, httptest.NewRequest(http.MethodGet, "/.sys/liveness", nil))
+
+		testingv2.Then(t, "直接放行且不注入主体",
+			testingv2.Expect(rw.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(subject, testingv2.Equal("")),
+		)
+	})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/innoai-tech/infra/pkg/otel"
+)
+
+// LogLevelHandler 创建日志级别控制中间件，在 /.sys/loglevel 路径查看（GET）与修改（PUT）日志级别。
+//
+// 修改时通过查询参数 level、logger 与 ttl 指定级别、日志记录器名称与有效期，如
+// PUT /.sys/loglevel?level=debug&logger=ListUser&ttl=10m；level 为空时移除对应覆盖。
+// token 不为空时要求携带 Authorization: Bearer <token>，否则仅在 enabled 时可访问。
+func LogLevelHandler(controller otel.LogLevelController, enabled bool, token string) func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return &logLevelHandler{
+			controller:  controller,
+			enabled:     controller != nil && (enabled || token != ""),
+			token:       token,
+			nextHandler: handler,
+		}
+	}
+}
+
+type logLevelHandler struct {
+	controller  otel.LogLevelController
+	enabled     bool
+	token       string
+	nextHandler http.Handler
+}
+
+func (h *logLevelHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !h.enabled || req.URL.Path != "/.sys/loglevel" {
+		h.nextHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	if h.token != "" {
+		token, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			http.Error(rw, "invalid token", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		query := req.URL.Query()
+
+		var ttl time.Duration
+		if v := query.Get("ttl"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(rw, "invalid ttl: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			ttl = d
+		}
+
+		if err := h.controller.SetLogLevel(query.Get("logger"), otel.LogLevel(strings.ToLower(query.Get("level"))), ttl); err != nil {
+			http.Error(rw, "invalid level: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		rw.Header().Set("Allow", "GET, PUT, POST")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(h.controller.LogLevels())
+}
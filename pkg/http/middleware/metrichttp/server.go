@@ -42,4 +42,11 @@ var (
 			Boundaries: SizeHistogramBoundaries,
 		}),
 	)
+
+	// ServerRateLimited 记录因限流被拒绝的入站 HTTP 请求数。
+	ServerRateLimited = metric.NewInt64Counter(
+		"http.server.rate_limited",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of inbound HTTP requests rejected by rate limiting"),
+	)
 )
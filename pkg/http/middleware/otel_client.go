@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"time"
 
-	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
@@ -16,6 +15,7 @@ import (
 	"github.com/octohelm/x/logr"
 
 	"github.com/innoai-tech/infra/pkg/http/middleware/metrichttp"
+	"github.com/innoai-tech/infra/pkg/otel"
 )
 
 // NewLogRoundTripper 创建一个带日志记录的 HTTP 客户端传输中间件。
@@ -27,7 +27,7 @@ func NewLogRoundTripper() func(roundTripper http.RoundTripper) http.RoundTripper
 	}
 }
 
-// LogRoundTripper 包装 http.RoundTripper，为每次请求添加日志、B3 传播和指标记录。
+// LogRoundTripper 包装 http.RoundTripper，为每次请求添加日志、trace 传播和指标记录。
 type LogRoundTripper struct {
 	nextRoundTripper http.RoundTripper
 }
@@ -38,8 +38,8 @@ func (rt *LogRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 
 	ctx := req.Context()
 
-	// 从上下文注入 b3 传播头
-	b3.New().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	// 按上下文中配置的传播格式注入传播头
+	otel.PropagatorFromContext(ctx).Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	ctx, log := logr.Start(ctx, "Request")
 	defer log.End()
@@ -50,7 +50,7 @@ func (rt *LogRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 
 	l := log.WithValues(
 		slog.String("http.method", req.Method),
-		slog.String("http.url", omitAuthorization(req.URL)),
+		slog.String("http.url", otel.RedactURL(req.URL)),
 		slog.String("http.client.duration", cost.String()),
 	)
 
@@ -1,27 +1,28 @@
 package middleware
 
 import (
-	"errors"
+	"bytes"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
-	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/octohelm/courier/pkg/courierhttp"
 	"github.com/octohelm/courier/pkg/courierhttp/util"
 	"github.com/octohelm/x/logr"
 
 	"github.com/innoai-tech/infra/pkg/http/middleware/metrichttp"
+	"github.com/innoai-tech/infra/pkg/otel"
 	"github.com/innoai-tech/infra/pkg/otel/openmetrics"
 )
 
@@ -51,6 +52,38 @@ func MetricHandler(metricReader sdkmetric.Reader) func(handler http.Handler) htt
 	}
 }
 
+// accessLogLevel 返回请求头 x-enable-log-level 指定的访问日志级别，未指定或无效时为 info。
+func accessLogLevel(req *http.Request) logr.Level {
+	if logLevel := req.Header.Get("x-enable-log-level"); logLevel != "" {
+		lvl, err := logr.ParseLevel(strings.ToLower(logLevel))
+		if err == nil {
+			return lvl
+		}
+	}
+	return logr.InfoLevel
+}
+
+// requestLogLevel 返回请求头 x-enable-log-level 指定的请求上下文日志级别，未启用调试模式时需携带匹配的 x-log-level-token。
+func requestLogLevel(req *http.Request, enableDebug bool, logLevelToken string) (logr.Level, bool) {
+	logLevel := req.Header.Get("x-enable-log-level")
+	if logLevel == "" {
+		return 0, false
+	}
+
+	if !enableDebug {
+		if logLevelToken == "" || subtle.ConstantTimeCompare([]byte(req.Header.Get("x-log-level-token")), []byte(logLevelToken)) != 1 {
+			return 0, false
+		}
+	}
+
+	lvl, err := logr.ParseLevel(strings.ToLower(logLevel))
+	if err != nil {
+		return 0, false
+	}
+
+	return lvl, true
+}
+
 func httpBasicAttrs(req *http.Request) []attribute.KeyValue {
 	if req.URL.Scheme == "" {
 		req.URL.Scheme = "http"
@@ -79,18 +112,59 @@ func httpRouteAttrs(statusCode int, info *courierhttp.OperationInfo, req *http.R
 	}
 }
 
+// LogAndMetricOption 表示用于配置日志与指标中间件的函数选项。
+type LogAndMetricOption func(*logAndMetricOptions)
+
+type logAndMetricOptions struct {
+	requestLogLevel bool
+	enableDebug     bool
+	logLevelToken   string
+}
+
+// EnableRequestLogLevel 使请求头 x-enable-log-level 同时作用于该请求上下文中的全部日志，
+// 仅在 enableDebug 为 true，或 logLevelToken 非空且与请求头 x-log-level-token 一致时生效，避免任意请求放大日志量。
+func EnableRequestLogLevel(enableDebug bool, logLevelToken string) LogAndMetricOption {
+	return func(o *logAndMetricOptions) {
+		o.requestLogLevel = true
+		o.enableDebug = enableDebug
+		o.logLevelToken = logLevelToken
+	}
+}
+
 // LogAndMetricHandler 创建日志记录与指标统计的 HTTP 中间件。
+//
+// 请求头 x-enable-log-level 可指定访问日志的输出级别。
 func LogAndMetricHandler() func(handler http.Handler) http.Handler {
+	return LogAndMetricHandlerWithOptions()
+}
+
+// LogAndMetricHandlerWithOptions 按选项创建日志记录与指标统计的 HTTP 中间件。
+func LogAndMetricHandlerWithOptions(opts ...LogAndMetricOption) func(handler http.Handler) http.Handler {
+	o := &logAndMetricOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return func(nextHandler http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			ctx := req.Context()
-			ctx = b3.New().Extract(ctx, propagation.HeaderCarrier(req.Header))
+
+			propagator := otel.PropagatorFromContext(ctx)
+			ctx = propagator.Extract(ctx, propagation.HeaderCarrier(req.Header))
 
 			startAt := time.Now()
 
 			info, _ := courierhttp.OperationInfoFromContext(ctx)
 
-			ctx, span := logr.FromContext(ctx).Start(ctx, info.ID)
+			// 请求头指定的日志级别作用于该请求上下文中的全部日志
+			if o.requestLogLevel {
+				if lvl, ok := requestLogLevel(req, o.enableDebug, o.logLevelToken); ok {
+					ctx = otel.ContextWithLogLevel(ctx, lvl)
+				}
+			}
+
+			// http.route 作为 span 起始属性，供按路由的采样规则使用
+			ctx, span := logr.FromContext(ctx).Start(ctx, info.ID, slog.String("http.route", info.Route))
 			defer func() {
 				span.End()
 			}()
@@ -104,17 +178,17 @@ func LogAndMetricHandler() func(handler http.Handler) http.Handler {
 
 			loggerRw := newLoggerResponseWriter(rw)
 
-			b3.New().Inject(ctx, propagation.HeaderCarrier(loggerRw.Header()))
+			// 认证中间件作为全局中间件时，认证主体已注入上下文
+			if p, ok := PrincipalFromContext(ctx); ok {
+				loggerRw.recordPrincipal(p)
+				span.SetAttributes(attribute.Key("enduser.id").String(p.Subject))
+			}
+
+			propagator.Inject(ctx, propagation.HeaderCarrier(loggerRw.Header()))
 
 			nextHandler.ServeHTTP(loggerRw, req.WithContext(ctx))
 
-			enabledLevel := logr.InfoLevel
-			if logLevel := req.Header.Get("x-enable-log-level"); logLevel != "" {
-				lvl, err := logr.ParseLevel(strings.ToLower(logLevel))
-				if err == nil {
-					enabledLevel = lvl
-				}
-			}
+			enabledLevel := accessLogLevel(req)
 
 			requestCost := time.Since(startAt)
 			requestHeader := req.Header
@@ -123,18 +197,26 @@ func LogAndMetricHandler() func(handler http.Handler) http.Handler {
 				slog.String("http.client_ip", util.ClientIP(req)),
 				slog.String("http.method", req.Method),
 				slog.String("http.proto", req.Proto),
-				slog.String("http.url", omitAuthorization(req.URL)),
+				slog.String("http.url", otel.RedactURL(req.URL)),
 				slog.Int("http.status_code", loggerRw.statusCode),
-				slog.String("user_agent.original", requestHeader.Get("User-Agent")),
+				slog.String("user_agent.original", otel.Redact("user_agent.original", requestHeader.Get("User-Agent"))),
 				slog.String("http.server.duration", fmt.Sprintf("%s", requestCost)),
 			)
 
-			if loggerRw.err != nil {
+			if loggerRw.principal != nil {
+				l = l.WithValues(slog.String("enduser.id", loggerRw.principal.Subject))
+			}
+
+			errInfo := loggerRw.errorInfo()
+
+			if errInfo != nil && errInfo.err != nil {
+				l = l.WithValues(errInfo.attrs()...)
+
 				if loggerRw.statusCode >= http.StatusInternalServerError {
-					l.Error(loggerRw.err)
+					l.Error(errInfo.err)
 				} else {
 					if isLevelEnabled(logr.WarnLevel)(enabledLevel) {
-						l.Warn(loggerRw.err)
+						l.Warn(errInfo.err)
 					}
 				}
 			} else {
@@ -143,7 +225,17 @@ func LogAndMetricHandler() func(handler http.Handler) http.Handler {
 				}
 			}
 
-			metricsAttrs := append(metricBasicAttrs, httpRouteAttrs(loggerRw.statusCode, info, req)...)
+			routeAttrs := httpRouteAttrs(loggerRw.statusCode, info, req)
+			if errInfo != nil {
+				if errorType := errInfo.errorType(loggerRw.statusCode); errorType != "" {
+					routeAttrs = append(routeAttrs, attribute.Key("error.type").String(errorType))
+				}
+			}
+
+			// 响应状态写入 span，供采样时保留 5xx 请求
+			trace.SpanFromContext(ctx).SetAttributes(routeAttrs...)
+
+			metricsAttrs := append(metricBasicAttrs, routeAttrs...)
 
 			metrichttp.ServerDuration.Record(ctx, requestCost.Seconds(), metric.WithAttributes(metricsAttrs...))
 			metrichttp.ServerRequestSize.Record(ctx, req.ContentLength, metric.WithAttributes(metricsAttrs...))
@@ -185,6 +277,25 @@ type loggerResponseWriter struct {
 	statusCode    int
 	written       int64
 	err           error
+
+	// errBody 为截断至 maxErrorParseSize 的错误响应体
+	errBody          bytes.Buffer
+	errBodyTruncated bool
+
+	// principal 为 AuthHandler 记录的认证主体
+	principal *Principal
+}
+
+func (rw *loggerResponseWriter) recordPrincipal(p *Principal) {
+	rw.principal = p
+}
+
+// errorInfo 返回本次响应的错误分类；非错误响应返回 nil。
+func (rw *loggerResponseWriter) errorInfo() *errorInfo {
+	if rw.err == nil && rw.statusCode < http.StatusBadRequest {
+		return nil
+	}
+	return classifyError(rw.err, rw.errBody.Bytes(), rw.errBodyTruncated)
 }
 
 func (rw *loggerResponseWriter) WriteError(err error) {
@@ -203,8 +314,13 @@ func (rw *loggerResponseWriter) Write(data []byte) (int, error) {
 	if rw.statusCode == 0 {
 		rw.WriteHeader(http.StatusOK)
 	}
-	if rw.err == nil && rw.statusCode >= http.StatusBadRequest {
-		rw.err = errors.New(string(data))
+	if rw.statusCode >= http.StatusBadRequest && !rw.errBodyTruncated {
+		if remain := maxErrorParseSize - rw.errBody.Len(); len(data) > remain {
+			rw.errBody.Write(data[:remain])
+			rw.errBodyTruncated = true
+		} else {
+			rw.errBody.Write(data)
+		}
 	}
 	n, err := rw.ResponseWriter.Write(data)
 	rw.written += int64(n)
@@ -218,13 +334,3 @@ func (rw *loggerResponseWriter) writeHeader(statusCode int) {
 		rw.headerWritten = true
 	}
 }
-
-func omitAuthorization(u *url.URL) string {
-	query := u.Query()
-
-	query.Del("authorization")
-	query.Del("x-param-header-Authorization")
-
-	u.RawQuery = query.Encode()
-	return u.String()
-}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+)
+
+// maxErrorBodySize 为记录到日志中的错误响应体上限，避免大体积 4xx 响应进入日志。
+const maxErrorBodySize = 1024
+
+// maxErrorParseSize 为解析错误 key 与来源时缓存的错误响应体上限，超出时不再解析。
+const maxErrorParseSize = 64 * 1024
+
+// statusCodeError 为携带 HTTP 状态码的错误。
+type statusCodeError interface {
+	StatusCode() int
+}
+
+// errorResponse 兼容 courier statuserror 写出的错误响应体。
+type errorResponse struct {
+	Code    int      `json:"code"`
+	Key     string   `json:"key"`
+	Msg     string   `json:"msg"`
+	Sources []string `json:"sources"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Key     string `json:"key"`
+		Message string `json:"message"`
+		Source  string `json:"source"`
+	} `json:"errors"`
+}
+
+// errorInfo 为访问日志与指标使用的错误分类。
+type errorInfo struct {
+	err     error
+	key     string
+	code    int
+	sources []string
+	// statusError 错误是否为 courier statuserror 错误，其 key 由服务定义、取值有限
+	statusError bool
+}
+
+// errorType 返回指标的 error.type：courier statuserror 错误为其 key，否则为状态码，避免响应体中的任意 key 放大指标基数；非错误响应返回空。
+func (e *errorInfo) errorType(statusCode int) string {
+	if statusCode < http.StatusBadRequest {
+		return ""
+	}
+	if e.statusError && e.key != "" {
+		return e.key
+	}
+	return strconv.Itoa(statusCode)
+}
+
+// attrs 返回访问日志中的错误属性；source.service 为错误最初产生的服务，经多个服务传递时 source.services 为完整链路。
+func (e *errorInfo) attrs() []any {
+	attrs := make([]any, 0, 4)
+
+	if e.key != "" {
+		attrs = append(attrs, slog.String("error.key", e.key))
+	}
+	if e.code != 0 {
+		attrs = append(attrs, slog.Int("error.code", e.code))
+	}
+	if len(e.sources) > 0 {
+		attrs = append(attrs, slog.String("source.service", e.sources[0]))
+	}
+	if len(e.sources) > 1 {
+		attrs = append(attrs, slog.String("source.services", strings.Join(e.sources, ",")))
+	}
+
+	return attrs
+}
+
+func (e *errorInfo) addSource(source string) {
+	if source != "" && !slices.Contains(e.sources, source) {
+		e.sources = append(e.sources, source)
+	}
+}
+
+// classifyError 基于 WriteError 传入的错误与错误响应体得到错误分类。
+//
+// courier statuserror 错误直接取其 key、状态码与来源；否则解析未超出 maxErrorParseSize 的完整响应体，
+// 记录到日志中的消息截断至 maxErrorBodySize。
+func classifyError(err error, body []byte, truncated bool) *errorInfo {
+	info := &errorInfo{err: err}
+
+	if resp := (*statuserror.ErrorResponse)(nil); errors.As(err, &resp) {
+		info.statusError = true
+		info.code = resp.Code
+		for _, d := range resp.Errors {
+			if info.key == "" {
+				info.key = d.Key
+			}
+			info.addSource(d.Source)
+		}
+		return info
+	}
+
+	if d := (*statuserror.Descriptor)(nil); errors.As(err, &d) {
+		info.statusError = true
+		info.code = d.Code
+		info.key = d.Key
+		info.addSource(d.Source)
+		return info
+	}
+
+	if sce := statusCodeError(nil); errors.As(err, &sce) {
+		info.code = sce.StatusCode()
+	}
+
+	resp := &errorResponse{}
+	if !truncated && len(body) > 0 && json.Unmarshal(body, resp) == nil {
+		info.key = resp.Key
+		if resp.Code != 0 {
+			info.code = resp.Code
+		}
+		for _, source := range resp.Sources {
+			info.addSource(source)
+		}
+
+		for _, e := range resp.Errors {
+			if info.key == "" {
+				info.key = e.Key
+			}
+			info.addSource(e.Source)
+		}
+
+		if info.err == nil && resp.Msg != "" {
+			info.err = errors.New(truncateErrorMessage(resp.Msg, false))
+		}
+	}
+
+	if info.err == nil && len(body) > 0 {
+		info.err = errors.New(truncateErrorMessage(string(bytes.TrimSpace(body)), truncated))
+	}
+
+	return info
+}
+
+// truncateErrorMessage 将记录到日志中的错误消息截断至 maxErrorBodySize。
+func truncateErrorMessage(msg string, truncated bool) string {
+	if len(msg) > maxErrorBodySize {
+		msg = strings.ToValidUTF8(msg[:maxErrorBodySize], "")
+		truncated = true
+	}
+	if truncated {
+		msg += "...(truncated)"
+	}
+	return msg
+}
+
+// writeStatusError 写出与 courier statuserror 兼容的错误响应。
+func writeStatusError(rw http.ResponseWriter, statusCode int, key string, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(rw).Encode(map[string]any{
+		"code": statusCode,
+		"key":  key,
+		"msg":  msg,
+	})
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/octohelm/courier/pkg/statuserror"
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Run("解析错误响应体", func(t *testing.T) {
+		body := `{"code":400,"msg":"` + strings.Repeat("x", 2*maxErrorBodySize) + `","errors":[{"key":"InvalidName","source":"org"},{"key":"InvalidID","source":"gateway"}]}`
+
+		info := classifyError(nil, []byte(body), false)
+
+		testingv2.Then(t, "从完整响应体取得 key 与来源，日志消息被截断，错误类型为状态码",
+			testingv2.Expect(info.key, testingv2.Equal("InvalidName")),
+			testingv2.Expect(info.code, testingv2.Equal(400)),
+			testingv2.Expect(info.errorType(400), testingv2.Equal("400")),
+			testingv2.Expect(info.sources, testingv2.Equal([]string{"org", "gateway"})),
+			testingv2.Expect(strings.HasSuffix(info.err.Error(), "...(truncated)"), testingv2.Equal(true)),
+			testingv2.Expect(len(info.err.Error()) <= maxErrorBodySize+len("...(truncated)"), testingv2.Equal(true)),
+			testingv2.Expect(attrValues(info.attrs()), testingv2.Equal(map[string]string{
+				"error.key":       "InvalidName",
+				"error.code":      "400",
+				"source.service":  "org",
+				"source.services": "org,gateway",
+			})),
+		)
+	})
+
+	t.Run("statuserror 错误", func(t *testing.T) {
+		info := classifyError(&statuserror.Descriptor{Code: 400, Key: "InvalidName", Source: "org"}, nil, false)
+
+		testingv2.Then(t, "错误类型为错误 key",
+			testingv2.Expect(info.errorType(400), testingv2.Equal("InvalidName")),
+			testingv2.Expect(info.sources, testingv2.Equal([]string{"org"})),
+		)
+	})
+
+	t.Run("非 JSON 响应体", func(t *testing.T) {
+		info := classifyError(nil, []byte("bad gateway\n"), false)
+
+		testingv2.Then(t, "以响应体作为错误消息，错误类型为状态码",
+			testingv2.Expect(info.err.Error(), testingv2.Equal("bad gateway")),
+			testingv2.Expect(info.errorType(502), testingv2.Equal("502")),
+		)
+	})
+
+	t.Run("携带状态码的错误", func(t *testing.T) {
+		info := classifyError(&codeError{code: 409}, nil, false)
+
+		testingv2.Then(t, "取错误的状态码",
+			testingv2.Expect(info.code, testingv2.Equal(409)),
+			testingv2.Expect(info.errorType(200), testingv2.Equal("")),
+		)
+	})
+}
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return "conflict"
+}
+
+func (e *codeError) StatusCode() int {
+	return e.code
+}
+
+func attrValues(attrs []any) map[string]string {
+	values := map[string]string{}
+	for _, a := range attrs {
+		if attr, ok := a.(slog.Attr); ok {
+			values[attr.Key] = attr.Value.String()
+		}
+	}
+	return values
+}
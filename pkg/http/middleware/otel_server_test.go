@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/octohelm/x/logr"
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestRequestLogLevel(t *testing.T) {
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/orgs", nil)
+		req.Header.Set("x-enable-log-level", "DEBUG")
+		if token != "" {
+			req.Header.Set("x-log-level-token", token)
+		}
+		return req
+	}
+
+	t.Run("启用调试模式", func(t *testing.T) {
+		lvl, ok := requestLogLevel(newRequest(""), true, "")
+
+		testingv2.Then(t, "直接使用请求头指定的级别",
+			testingv2.Expect(ok, testingv2.Equal(true)),
+			testingv2.Expect(lvl, testingv2.Equal(logr.DebugLevel)),
+		)
+	})
+
+	t.Run("未启用调试模式", func(t *testing.T) {
+		_, withoutToken := requestLogLevel(newRequest(""), false, "")
+		_, missingToken := requestLogLevel(newRequest(""), false, "s3cr3t")
+		_, wrongToken := requestLogLevel(newRequest("other"), false, "s3cr3t")
+		lvl, matched := requestLogLevel(newRequest("s3cr3t"), false, "s3cr3t")
+
+		testingv2.Then(t, "仅在 token 一致时生效",
+			testingv2.Expect(withoutToken, testingv2.Equal(false)),
+			testingv2.Expect(missingToken, testingv2.Equal(false)),
+			testingv2.Expect(wrongToken, testingv2.Equal(false)),
+			testingv2.Expect(matched, testingv2.Equal(true)),
+			testingv2.Expect(lvl, testingv2.Equal(logr.DebugLevel)),
+		)
+	})
+
+	t.Run("无效级别", func(t *testing.T) {
+		req := newRequest("")
+		req.Header.Set("x-enable-log-level", "verbose")
+
+		_, ok := requestLogLevel(req, true, "")
+
+		testingv2.Then(t, "忽略请求头",
+			testingv2.Expect(ok, testingv2.Equal(false)),
+		)
+	})
+}
+
+func TestAccessLogLevel(t *testing.T) {
+	newRequest := func(level string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/orgs", nil)
+		if level != "" {
+			req.Header.Set("x-enable-log-level", level)
+		}
+		return req
+	}
+
+	testingv2.Then(t, "无需调试模式或 token，未指定或无效时为 info",
+		testingv2.Expect(accessLogLevel(newRequest("ERROR")), testingv2.Equal(logr.ErrorLevel)),
+		testingv2.Expect(accessLogLevel(newRequest("")), testingv2.Equal(logr.InfoLevel)),
+		testingv2.Expect(accessLogLevel(newRequest("verbose")), testingv2.Equal(logr.InfoLevel)),
+	)
+}
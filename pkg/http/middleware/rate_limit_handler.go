@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/octohelm/courier/pkg/courierhttp"
+	"github.com/octohelm/courier/pkg/courierhttp/util"
+	"github.com/octohelm/x/logr"
+
+	"github.com/innoai-tech/infra/pkg/http/middleware/metrichttp"
+)
+
+// RateLimitKeyFunc 返回请求的限流键，返回空字符串时该请求不限流。
+type RateLimitKeyFunc func(req *http.Request) string
+
+// RateLimitByClientIP 按客户端 IP 限流。
+func RateLimitByClientIP() RateLimitKeyFunc {
+	return func(req *http.Request) string {
+		return util.ClientIP(req)
+	}
+}
+
+// RateLimitByHeader 按请求头的值限流，请求头为空时不限流。
+func RateLimitByHeader(name string) RateLimitKeyFunc {
+	return func(req *http.Request) string {
+		return req.Header.Get(name)
+	}
+}
+
+// RateLimitBySubject 按认证主体限流，subject 从请求上下文中取得主体，未认证时不限流。
+func RateLimitBySubject(subject func(ctx context.Context) string) RateLimitKeyFunc {
+	return func(req *http.Request) string {
+		return subject(req.Context())
+	}
+}
+
+// RateLimitRule 为限流规则。
+type RateLimitRule struct {
+	// Operation 匹配 OperationInfo 的 ID 或 Route，为空时匹配全部请求
+	Operation string
+	RateLimit
+	// Key 为空时按客户端 IP 限流
+	Key RateLimitKeyFunc
+}
+
+func (rule *RateLimitRule) match(info *courierhttp.OperationInfo) bool {
+	return rule.Operation == "" || (info != nil && (rule.Operation == info.ID || rule.Operation == info.Route))
+}
+
+// RateLimitHandler 创建限流中间件，需作为路由中间件使用以取得 OperationInfo。
+//
+// 请求按顺序匹配第一条规则，响应携带 X-RateLimit-Limit、X-RateLimit-Remaining 与 X-RateLimit-Reset（秒）；
+// 超出限额时返回 429 与 Retry-After，并记录 http.server.rate_limited 指标。
+// store 出错时放行请求并记录警告日志。
+func RateLimitHandler(store RateLimitStore, rules ...RateLimitRule) func(handler http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return &rateLimitHandler{
+			store:       store,
+			rules:       rules,
+			nextHandler: handler,
+		}
+	}
+}
+
+type rateLimitHandler struct {
+	store       RateLimitStore
+	rules       []RateLimitRule
+	nextHandler http.Handler
+}
+
+func (h *rateLimitHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	info, _ := courierhttp.OperationInfoFromContext(ctx)
+
+	for i := range h.rules {
+		rule := &h.rules[i]
+		if !rule.match(info) {
+			continue
+		}
+
+		keyFunc := rule.Key
+		if keyFunc == nil {
+			keyFunc = RateLimitByClientIP()
+		}
+
+		key := keyFunc(req)
+		if key == "" {
+			break
+		}
+
+		// 以规则序号区分不同规则下的同一限流键
+		result, err := h.store.Take(ctx, strconv.Itoa(i)+":"+key, rule.RateLimit)
+		if err != nil {
+			logr.FromContext(ctx).Warn(err)
+			break
+		}
+
+		header := rw.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			route := ""
+			if info != nil {
+				route = info.Route
+			}
+
+			metrichttp.ServerRateLimited.Add(ctx, 1, metric.WithAttributes(
+				attribute.Key("http.route").String(route),
+				attribute.Key("http.request.method").String(req.Method),
+			))
+
+			rw.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			writeStatusError(rw, http.StatusTooManyRequests, "TooManyRequests", "too many requests")
+			return
+		}
+
+		break
+	}
+
+	h.nextHandler.ServeHTTP(rw, req)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	h := RateLimitHandler(newTestRateLimitStore(&now), RateLimitRule{
+		RateLimit: RateLimit{Limit: 1, Period: 10 * time.Second},
+		Key:       RateLimitByHeader("x-user"),
+	})(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	do := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/orgs", nil)
+		if user != "" {
+			req.Header.Set("x-user", user)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	t.Run("放行并返回限流响应头", func(t *testing.T) {
+		rw := do("alice")
+
+		testingv2.Then(t, "响应携带限额、剩余额度与恢复秒数",
+			testingv2.Expect(rw.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(rw.Header().Get("X-RateLimit-Limit"), testingv2.Equal("1")),
+			testingv2.Expect(rw.Header().Get("X-RateLimit-Remaining"), testingv2.Equal("0")),
+			testingv2.Expect(rw.Header().Get("X-RateLimit-Reset"), testingv2.Equal("10")),
+		)
+	})
+
+	t.Run("超出限额", func(t *testing.T) {
+		now = now.Add(4 * time.Second)
+
+		rw := do("alice")
+
+		testingv2.Then(t, "返回 429 与 Retry-After",
+			testingv2.Expect(rw.Code, testingv2.Equal(http.StatusTooManyRequests)),
+			testingv2.Expect(rw.Header().Get("Retry-After"), testingv2.Equal("6")),
+			testingv2.Expect(rw.Header().Get("X-RateLimit-Reset"), testingv2.Equal("6")),
+			testingv2.Expect(strings.Contains(rw.Body.String(), `"key":"TooManyRequests"`), testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("不同限流键与无限流键", func(t *testing.T) {
+		other := do("bob")
+		anonymous := do("")
+
+		testingv2.Then(t, "不同键互不影响，键为空时不限流",
+			testingv2.Expect(other.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(anonymous.Code, testingv2.Equal(http.StatusNoContent)),
+			testingv2.Expect(anonymous.Header().Get("X-RateLimit-Limit"), testingv2.Equal("")),
+		)
+	})
+}
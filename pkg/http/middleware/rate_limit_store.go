@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitAlgorithm 为限流算法。
+type RateLimitAlgorithm string
+
+const (
+	// RateLimitTokenBucket 为令牌桶：桶容量为 Limit，每 Period 补满，允许突发。
+	RateLimitTokenBucket RateLimitAlgorithm = "token-bucket"
+	// RateLimitSlidingWindow 为滑动窗口：按前后两个窗口加权估算最近 Period 内的请求数。
+	RateLimitSlidingWindow RateLimitAlgorithm = "sliding-window"
+)
+
+// RateLimit 为单个限流键的限额。
+type RateLimit struct {
+	Algorithm RateLimitAlgorithm
+	// Limit 为每个 Period 允许的请求数
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitResult 为一次限流判断的结果。
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter 为不再有请求时额度完全恢复（Remaining 回到 Limit）所需时间，两种算法含义一致
+	ResetAfter time.Duration
+	// RetryAfter 为被拒绝时下次可放行所需时间
+	RetryAfter time.Duration
+}
+
+// RateLimitStore 为限流状态存储，可替换为 Redis 等共享存储以在多实例间限流。
+type RateLimitStore interface {
+	// Take 对 key 消耗一次额度并返回结果
+	Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error)
+}
+
+// rateLimitSweepInterval 为内存存储清理闲置限流键的间隔。
+const rateLimitSweepInterval = time.Minute
+
+// NewMemoryRateLimitStore 创建进程内的限流存储，闲置超过一个周期的限流键会被定期清理。
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		states:    map[string]rateLimitState{},
+		limits:    map[string]RateLimit{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+type rateLimitState interface {
+	take(now time.Time, limit RateLimit) *RateLimitResult
+	idle(now time.Time, limit RateLimit) bool
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	states    map[string]rateLimitState
+	limits    map[string]RateLimit
+	lastSweep time.Time
+
+	// now 为当前时间，测试中可替换
+	now func() time.Time
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error) {
+	if limit.Limit <= 0 || limit.Period <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %d/%s", limit.Limit, limit.Period)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	state, ok := s.states[key]
+	if !ok {
+		switch limit.Algorithm {
+		case RateLimitSlidingWindow:
+			state = &slidingWindow{}
+		case RateLimitTokenBucket, "":
+			state = &tokenBucket{tokens: float64(limit.Limit), updatedAt: now}
+		default:
+			return nil, fmt.Errorf("unsupported rate limit algorithm %q", limit.Algorithm)
+		}
+
+		s.states[key] = state
+		s.limits[key] = limit
+	}
+
+	return state.take(now, limit), nil
+}
+
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	s.lastSweep = now
+
+	for key, state := range s.states {
+		if state.idle(now, s.limits[key]) {
+			delete(s.states, key)
+			delete(s.limits, key)
+		}
+	}
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+func (b *tokenBucket) take(now time.Time, limit RateLimit) *RateLimitResult {
+	capacity := float64(limit.Limit)
+	// 每纳秒补充的令牌数
+	rate := capacity / float64(limit.Period)
+
+	b.tokens = min(capacity, b.tokens+float64(now.Sub(b.updatedAt))*rate)
+	b.updatedAt = now
+
+	r := &RateLimitResult{Limit: limit.Limit}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		r.Allowed = true
+	} else {
+		r.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+
+	r.Remaining = int(math.Floor(b.tokens))
+	r.ResetAfter = time.Duration(math.Ceil((capacity - b.tokens) / rate))
+
+	return r
+}
+
+func (b *tokenBucket) idle(now time.Time, limit RateLimit) bool {
+	return now.Sub(b.updatedAt) >= limit.Period
+}
+
+type slidingWindow struct {
+	start    time.Time
+	previous int
+	current  int
+}
+
+func (w *slidingWindow) take(now time.Time, limit RateLimit) *RateLimitResult {
+	start := now.Truncate(limit.Period)
+
+	if !start.Equal(w.start) {
+		if start.Sub(w.start) == limit.Period {
+			w.previous = w.current
+		} else {
+			w.previous = 0
+		}
+		w.current = 0
+		w.start = start
+	}
+
+	elapsed := now.Sub(start)
+	// 前一窗口在最近 Period 内所占的比例
+	weight := 1 - float64(elapsed)/float64(limit.Period)
+	count := float64(w.previous)*weight + float64(w.current)
+
+	r := &RateLimitResult{
+		Limit: limit.Limit,
+	}
+
+	if count+1 <= float64(limit.Limit) {
+		w.current++
+		count++
+		r.Allowed = true
+	} else {
+		r.RetryAfter = w.retryAfter(elapsed, limit)
+	}
+
+	r.Remaining = max(0, limit.Limit-int(math.Ceil(count)))
+	r.ResetAfter = w.resetAfter(elapsed, limit)
+
+	return r
+}
+
+// resetAfter 返回加权计数衰减为 0 所需时间：当前窗口有请求时需等到下一窗口结束，
+// 仅前一窗口有请求时等到当前窗口结束。
+func (w *slidingWindow) resetAfter(elapsed time.Duration, limit RateLimit) time.Duration {
+	switch {
+	case w.current > 0:
+		return 2*limit.Period - elapsed
+	case w.previous > 0:
+		return limit.Period - elapsed
+	default:
+		return 0
+	}
+}
+
+// retryAfter 返回前一窗口的权重衰减到足以放行一次请求所需的时间，不超过当前窗口剩余时间。
+func (w *slidingWindow) retryAfter(elapsed time.Duration, limit RateLimit) time.Duration {
+	remaining := limit.Period - elapsed
+
+	if w.previous == 0 || w.current+1 > limit.Limit {
+		return remaining
+	}
+
+	// previous * (1 - t/Period) + current + 1 <= Limit
+	t := time.Duration((1 - float64(limit.Limit-w.current-1)/float64(w.previous)) * float64(limit.Period))
+
+	return min(max(t-elapsed, time.Millisecond), remaining)
+}
+
+func (w *slidingWindow) idle(now time.Time, limit RateLimit) bool {
+	return now.Sub(w.start) >= 2*limit.Period
+}
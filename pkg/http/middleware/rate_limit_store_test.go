@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+// newTestRateLimitStore 创建以 now 为当前时间的内存限流存储。
+func newTestRateLimitStore(now *time.Time) *memoryRateLimitStore {
+	s := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	s.now = func() time.Time {
+		return *now
+	}
+	s.lastSweep = *now
+	return s
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	take := func(t *testing.T, s RateLimitStore, key string, limit RateLimit) *RateLimitResult {
+		t.Helper()
+
+		return testingv2.MustValue(t, func() (*RateLimitResult, error) {
+			return s.Take(ctx, key, limit)
+		})
+	}
+
+	t.Run("令牌桶按速率补充", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		s := newTestRateLimitStore(&now)
+		limit := RateLimit{Algorithm: RateLimitTokenBucket, Limit: 2, Period: time.Second}
+
+		first := take(t, s, "a", limit)
+		second := take(t, s, "a", limit)
+		denied := take(t, s, "a", limit)
+
+		testingv2.Then(t, "桶容量内放行，桶空后拒绝，ResetAfter 为补满所需时间",
+			testingv2.Expect(first.Allowed, testingv2.Equal(true)),
+			testingv2.Expect(first.Remaining, testingv2.Equal(1)),
+			testingv2.Expect(first.ResetAfter.Round(time.Millisecond), testingv2.Equal(500*time.Millisecond)),
+			testingv2.Expect(second.Allowed, testingv2.Equal(true)),
+			testingv2.Expect(second.Remaining, testingv2.Equal(0)),
+			testingv2.Expect(second.ResetAfter.Round(time.Millisecond), testingv2.Equal(time.Second)),
+			testingv2.Expect(denied.Allowed, testingv2.Equal(false)),
+			testingv2.Expect(denied.RetryAfter.Round(time.Millisecond), testingv2.Equal(500*time.Millisecond)),
+		)
+
+		now = now.Add(250 * time.Millisecond)
+		halfway := take(t, s, "a", limit)
+
+		now = now.Add(350 * time.Millisecond)
+		refilled := take(t, s, "a", limit)
+
+		testingv2.Then(t, "经过 RetryAfter 后补充出一个令牌",
+			testingv2.Expect(halfway.Allowed, testingv2.Equal(false)),
+			testingv2.Expect(halfway.RetryAfter.Round(time.Millisecond), testingv2.Equal(250*time.Millisecond)),
+			testingv2.Expect(refilled.Allowed, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("滑动窗口按前一窗口加权", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		s := newTestRateLimitStore(&now)
+		limit := RateLimit{Algorithm: RateLimitSlidingWindow, Limit: 2, Period: time.Second}
+
+		first := take(t, s, "a", limit)
+		_ = take(t, s, "a", limit)
+		denied := take(t, s, "a", limit)
+
+		testingv2.Then(t, "窗口内超出限额时等到窗口结束，ResetAfter 为计数衰减为 0 所需时间",
+			testingv2.Expect(first.Allowed, testingv2.Equal(true)),
+			testingv2.Expect(first.Remaining, testingv2.Equal(1)),
+			testingv2.Expect(first.ResetAfter, testingv2.Equal(2*time.Second)),
+			testingv2.Expect(denied.Allowed, testingv2.Equal(false)),
+			testingv2.Expect(denied.RetryAfter, testingv2.Equal(time.Second)),
+			testingv2.Expect(denied.ResetAfter, testingv2.Equal(2*time.Second)),
+		)
+
+		now = now.Add(1500 * time.Millisecond)
+		rolled := take(t, s, "a", limit)
+		rolledDenied := take(t, s, "a", limit)
+
+		testingv2.Then(t, "进入下一窗口后前一窗口按剩余比例计入",
+			testingv2.Expect(rolled.Allowed, testingv2.Equal(true)),
+			testingv2.Expect(rolled.Remaining, testingv2.Equal(0)),
+			testingv2.Expect(rolled.ResetAfter, testingv2.Equal(1500*time.Millisecond)),
+			testingv2.Expect(rolledDenied.Allowed, testingv2.Equal(false)),
+			testingv2.Expect(rolledDenied.RetryAfter, testingv2.Equal(500*time.Millisecond)),
+		)
+
+		now = now.Add(5 * time.Second)
+		skipped := take(t, s, "a", limit)
+
+		testingv2.Then(t, "间隔超过一个窗口时不再计入此前的请求",
+			testingv2.Expect(skipped.Allowed, testingv2.Equal(true)),
+			testingv2.Expect(skipped.Remaining, testingv2.Equal(1)),
+		)
+	})
+
+	t.Run("清理闲置的限流键", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		s := newTestRateLimitStore(&now)
+		limit := RateLimit{Limit: 1, Period: time.Second}
+
+		_ = take(t, s, "idle", limit)
+
+		now = now.Add(rateLimitSweepInterval)
+		_ = take(t, s, "active", limit)
+
+		_, idle := s.states["idle"]
+		_, active := s.states["active"]
+
+		testingv2.Then(t, "闲置超过一个周期的限流键被清理",
+			testingv2.Expect(idle, testingv2.Equal(false)),
+			testingv2.Expect(active, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("无效限额", func(t *testing.T) {
+		_, err := NewMemoryRateLimitStore().Take(ctx, "a", RateLimit{Limit: 0, Period: time.Second})
+
+		testingv2.Then(t, "返回错误",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+}
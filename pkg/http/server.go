@@ -5,11 +5,13 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"runtime"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +28,8 @@ import (
 	"github.com/innoai-tech/infra/pkg/appinfo"
 	"github.com/innoai-tech/infra/pkg/configuration"
 	"github.com/innoai-tech/infra/pkg/http/middleware"
+	"github.com/innoai-tech/infra/pkg/http/tlsfile"
+	"github.com/innoai-tech/infra/pkg/otel"
 	otelmetric "github.com/innoai-tech/infra/pkg/otel/metric"
 )
 
@@ -36,6 +40,32 @@ type Server struct {
 	Addr string `flag:",omitzero,expose=http"`
 	// EnableDebug 启用调试模式
 	EnableDebug bool `flag:",omitzero"`
+	// LogLevelToken 访问 /.sys/loglevel 的 Bearer token，设置后无需启用调试模式；请求头 x-log-level-token 与之一致时请求头 x-enable-log-level 同样作用于请求内的全部日志
+	LogLevelToken string `flag:",omitzero,secret"`
+
+	// TLSCertFile TLS 证书文件，文件变更或触发重载时重新加载
+	TLSCertFile string `flag:",omitzero,volume=secret"`
+	// TLSKeyFile TLS 私钥文件
+	TLSKeyFile string `flag:",omitzero,volume=secret"`
+	// TLSClientCAFile 校验客户端证书的 CA 文件
+	TLSClientCAFile string `flag:",omitzero,volume=secret"`
+	// TLSClientAuth 客户端证书校验模式：none、request、require、verify-if-given、require-and-verify，默认配置了 TLSClientCAFile 时为 require-and-verify
+	TLSClientAuth string `flag:",omitzero"`
+
+	// AuthPublicPaths 无需认证的路径，逗号分隔，以 * 结尾时按前缀匹配，默认仅公开健康检查 /；/.sys/ 下的内置接口同样需要认证，需公开时如 /.sys/metrics 显式列出
+	AuthPublicPaths string `flag:",omitzero"`
+	// AuthBearerTokens 静态 Bearer token，逗号分隔，形如 subject:token
+	AuthBearerTokens string `flag:",omitzero,secret"`
+	// AuthJWKSFile 校验 JWT 的本地 JWKS 文件
+	AuthJWKSFile string `flag:",omitzero,volume=secret"`
+	// AuthJWTAudience 要求 JWT 的 aud 声明包含该值，需配置 AuthJWKSFile
+	AuthJWTAudience string `flag:",omitzero"`
+	// AuthJWTIssuer 要求 JWT 的 iss 声明与该值一致，需配置 AuthJWKSFile
+	AuthJWTIssuer string `flag:",omitzero"`
+	// AuthHtpasswdFile Basic 认证的 htpasswd 文件
+	AuthHtpasswdFile string `flag:",omitzero,volume=secret"`
+	// AuthClientCert 以已校验的 mTLS 客户端证书作为认证主体，需配置 TLSClientCAFile
+	AuthClientCert bool `flag:",omitzero"`
 
 	corsOptions []middleware.CORSOption
 
@@ -44,19 +74,26 @@ type Server struct {
 	svc  *http.Server
 
 	tlsProvider    Provider
+	tlsFile        *tlsfile.Provider
 	metricReader   sdkmetric.Reader
 	globalHandlers []handler.Middleware
 	routerHandlers []handler.Middleware
+	authenticators []middleware.Authenticator
 
 	info *appinfo.Info `inject:",opt"`
 
 	ready    sync.WaitGroup
+	readyErr error
 	endpoint atomic.Pointer[string]
 }
 
-// SetDefaults 根据 TLS 配置补齐默认监听地址。
+// SetDefaults 补齐认证的默认公开路径，并根据 TLS 配置补齐默认监听地址。
 func (s *Server) SetDefaults() {
-	if s.tlsProvider != nil {
+	if s.AuthPublicPaths == "" {
+		s.AuthPublicPaths = "/"
+	}
+
+	if s.tlsProvider != nil || s.TLSCertFile != "" {
 		if s.Addr == "" {
 			s.Addr = ":443"
 		}
@@ -83,9 +120,16 @@ func (s *Server) SetName(name string) {
 	s.name = name
 }
 
+// SetTLSProvider 设置 TLS 配置提供者，优先于 TLSCertFile 等配置。
+func (s *Server) SetTLSProvider(tlsProvider Provider) {
+	s.tlsProvider = tlsProvider
+}
+
 // SetTLSProvoder 设置 TLS 配置提供者。
+//
+// Deprecated: 使用 SetTLSProvider。
 func (s *Server) SetTLSProvoder(tlsProvider Provider) {
-	s.tlsProvider = tlsProvider
+	s.SetTLSProvider(tlsProvider)
 }
 
 // SetMetricReader 显式设置指标 reader，避免完全依赖上下文注入。
@@ -93,6 +137,11 @@ func (s *Server) SetMetricReader(reader sdkmetric.Reader) {
 	s.metricReader = reader
 }
 
+// SetAuthenticators 追加自定义认证方式，在配置的认证方式之后依次尝试。
+func (s *Server) SetAuthenticators(authenticators ...middleware.Authenticator) {
+	s.authenticators = append(s.authenticators, authenticators...)
+}
+
 // ApplyRouterHandlers 为业务路由追加中间件。
 func (s *Server) ApplyRouterHandlers(handlers ...handler.Middleware) {
 	s.routerHandlers = append(s.routerHandlers, handlers...)
@@ -131,12 +180,90 @@ func (s *Server) buildRouterHandlers(ctx context.Context) []handler.Middleware {
 		[]handler.Middleware{
 			middleware.ContextInjectorMiddleware(configuration.ContextInjectorFromContext(ctx)),
 			middleware.CompressHandlerMiddleware(gzip.DefaultCompression),
-			middleware.LogAndMetricHandler(),
+			middleware.LogAndMetricHandlerWithOptions(middleware.EnableRequestLogLevel(s.EnableDebug, s.LogLevelToken)),
 		},
 		s.routerHandlers,
 	)
 }
 
+// authHandlers 在配置了认证方式时返回认证中间件。
+//
+// 认证作为全局中间件，位于 CORS 之后、/.sys/ 内置接口之前，使内置接口同样需要认证，是否公开由 AuthPublicPaths 决定。
+func (s *Server) authHandlers() []handler.Middleware {
+	if len(s.authenticators) == 0 {
+		return nil
+	}
+
+	return []handler.Middleware{
+		middleware.AuthHandler(
+			middleware.Authenticators(s.authenticators...),
+			s.authPublicPaths()...,
+		),
+	}
+}
+
+func (s *Server) authPublicPaths() []string {
+	paths := make([]string, 0)
+	for p := range strings.SplitSeq(s.AuthPublicPaths, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func (s *Server) initAuthenticators() error {
+	if s.AuthClientCert && s.TLSClientCAFile == "" {
+		return errors.New("auth client cert requires tls client ca file")
+	}
+
+	if (s.AuthJWTAudience != "" || s.AuthJWTIssuer != "") && s.AuthJWKSFile == "" {
+		return errors.New("auth jwt audience or issuer requires auth jwks file")
+	}
+
+	authenticators := make([]middleware.Authenticator, 0, len(s.authenticators)+4)
+
+	if s.AuthBearerTokens != "" {
+		tokens, err := middleware.ParseBearerTokens(s.AuthBearerTokens)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, middleware.NewBearerTokenAuthenticator(tokens))
+	}
+
+	if s.AuthJWKSFile != "" {
+		jwks, err := middleware.LoadJWKSFile(s.AuthJWKSFile)
+		if err != nil {
+			return err
+		}
+		a, err := middleware.NewJWTAuthenticator(
+			jwks,
+			middleware.JWTAudience(s.AuthJWTAudience),
+			middleware.JWTIssuer(s.AuthJWTIssuer),
+		)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, a)
+	}
+
+	if s.AuthHtpasswdFile != "" {
+		users, err := middleware.LoadHtpasswdFile(s.AuthHtpasswdFile)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, middleware.NewBasicAuthenticator(cmp.Or(s.name, "infra"), users))
+	}
+
+	if s.AuthClientCert {
+		authenticators = append(authenticators, middleware.NewClientCertAuthenticator())
+	}
+
+	s.authenticators = append(authenticators, s.authenticators...)
+
+	return nil
+}
+
 func (s *Server) afterInit(ctx context.Context) error {
 	if s.svc != nil {
 		return nil
@@ -151,6 +278,24 @@ func (s *Server) afterInit(ctx context.Context) error {
 		}
 	}
 
+	if s.tlsProvider == nil && s.TLSCertFile != "" {
+		p, err := tlsfile.New(ctx, tlsfile.Options{
+			CertFile:     s.TLSCertFile,
+			KeyFile:      s.TLSKeyFile,
+			ClientCAFile: s.TLSClientCAFile,
+			ClientAuth:   tlsfile.ClientAuth(s.TLSClientAuth),
+		})
+		if err != nil {
+			return err
+		}
+		s.tlsFile = p
+		s.tlsProvider = p
+	}
+
+	if err := s.initAuthenticators(); err != nil {
+		return err
+	}
+
 	var r http.Handler = http.NewServeMux()
 
 	if s.root != nil {
@@ -161,11 +306,17 @@ func (s *Server) afterInit(ctx context.Context) error {
 		r = h
 	}
 
+	logLevelController, _ := otel.LogLevelControllerFromContext(ctx)
+
 	globalHandlers := slices.Concat(
 		[]handler.Middleware{
-			middleware.MetricHandler(metricReader),
 			middleware.DefaultCORS(s.corsOptions...),
+		},
+		s.authHandlers(),
+		[]handler.Middleware{
+			middleware.MetricHandler(metricReader),
 			middleware.PProfHandler(s.EnableDebug),
+			middleware.LogLevelHandler(logLevelController, s.EnableDebug, s.LogLevelToken),
 		},
 		s.globalHandlers,
 		[]handler.Middleware{
@@ -195,6 +346,26 @@ func (s *Server) Endpoint() string {
 	return ""
 }
 
+// WaitReady 等待服务监听就绪；监听失败时返回对应错误。
+func (s *Server) WaitReady(ctx context.Context) error {
+	if s.svc == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.ready.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return s.readyErr
+	}
+}
+
 // Serve 启动 HTTP 服务并记录最终监听地址。
 func (s *Server) Serve(ctx context.Context) error {
 	if s.svc == nil {
@@ -212,7 +383,9 @@ func (s *Server) Serve(ctx context.Context) error {
 
 	host, port, err := net.SplitHostPort(svc.Addr)
 	if err != nil {
-		return nil
+		s.readyErr = err
+		s.ready.Done()
+		return err
 	}
 	if host == "" {
 		host = "0.0.0.0"
@@ -220,6 +393,8 @@ func (s *Server) Serve(ctx context.Context) error {
 
 	ln, err := net.Listen("tcp", svc.Addr)
 	if err != nil {
+		s.readyErr = err
+		s.ready.Done()
 		return err
 	}
 	defer ln.Close()
@@ -238,6 +413,10 @@ func (s *Server) Serve(ctx context.Context) error {
 	s.ready.Done()
 
 	if s.tlsProvider != nil {
+		if s.tlsFile != nil {
+			go s.tlsFile.Watch(ctx)
+		}
+
 		svc.TLSConfig = s.tlsProvider.TLSConfig()
 		return svc.ServeTLS(ln, "", "")
 	}
@@ -245,11 +424,24 @@ func (s *Server) Serve(ctx context.Context) error {
 	return svc.Serve(ln)
 }
 
+// Reload 重新加载 TLS 证书文件，由信号策略触发重载时调用，例如 configuration.ReloadOnHangup()。
+//
+// 加载失败时继续使用之前的证书。
+func (s *Server) Reload(ctx context.Context) error {
+	if s.tlsFile == nil {
+		return nil
+	}
+	return s.tlsFile.Reload(ctx)
+}
+
 // Shutdown 优雅关闭底层 HTTP 服务。
 func (s *Server) Shutdown(ctx context.Context) error {
 	if s.svc == nil {
 		return nil
 	}
+	if s.tlsFile != nil {
+		s.tlsFile.Close()
+	}
 	return s.svc.Shutdown(ctx)
 }
 
@@ -0,0 +1,237 @@
+package tlsfile
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+
+	"github.com/octohelm/x/logr"
+
+	"github.com/innoai-tech/infra/pkg/otel/metric"
+)
+
+// CertificateExpiry 记录当前服务证书的过期时间（Unix 秒）。
+var CertificateExpiry = metric.NewFloat64Gauge(
+	"tls.certificate.expiry",
+	metric.WithUnit("s"),
+	metric.WithDescription("Unix timestamp at which the serving TLS certificate expires"),
+)
+
+// watchInterval 为检查证书文件变更的间隔。
+const watchInterval = 10 * time.Second
+
+// ClientAuth 为客户端证书校验模式：none、request、require、verify-if-given、require-and-verify。
+type ClientAuth string
+
+const (
+	ClientAuthNone             ClientAuth = "none"
+	ClientAuthRequest          ClientAuth = "request"
+	ClientAuthRequire          ClientAuth = "require"
+	ClientAuthVerifyIfGiven    ClientAuth = "verify-if-given"
+	ClientAuthRequireAndVerify ClientAuth = "require-and-verify"
+)
+
+func (c ClientAuth) tlsClientAuth() (tls.ClientAuthType, error) {
+	switch c {
+	case ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest:
+		return tls.RequestClientCert, nil
+	case ClientAuthRequire:
+		return tls.RequireAnyClientCert, nil
+	case ClientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequireAndVerify:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("unsupported tls client auth %q", c)
+	}
+}
+
+// Options 为 TLS 证书文件配置。
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile 为空时不校验客户端证书
+	ClientCAFile string
+	// ClientAuth 为空时，配置了 ClientCAFile 则为 require-and-verify，否则为 none
+	ClientAuth ClientAuth
+}
+
+// New 加载证书文件并创建 Provider，ctx 用于记录证书过期时间指标。
+func New(ctx context.Context, opts Options) (*Provider, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("tls cert file and key file are required")
+	}
+
+	if opts.ClientAuth == "" {
+		opts.ClientAuth = ClientAuthNone
+		if opts.ClientCAFile != "" {
+			opts.ClientAuth = ClientAuthRequireAndVerify
+		}
+	}
+
+	clientAuth, err := opts.ClientAuth.tlsClientAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ClientCAFile == "" && (clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) {
+		return nil, fmt.Errorf("tls client auth %s requires client ca file", opts.ClientAuth)
+	}
+
+	p := &Provider{
+		opts:       opts,
+		clientAuth: clientAuth,
+		done:       make(chan struct{}),
+	}
+
+	if err := p.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Provider 从证书文件提供 TLS 配置，Watch 感知文件变更或调用 Reload 时重新加载，已建立的连接不受影响。
+type Provider struct {
+	opts       Options
+	clientAuth tls.ClientAuthType
+
+	config  atomic.Pointer[tls.Config]
+	modTime atomic.Pointer[time.Time]
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// TLSConfig 返回 TLS 配置，每次握手使用最近一次加载的证书与客户端 CA。
+func (p *Provider) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &p.config.Load().Certificates[0], nil
+		},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			return p.config.Load(), nil
+		},
+	}
+}
+
+// Reload 重新加载证书文件，加载失败时继续使用之前的证书。
+func (p *Provider) Reload(ctx context.Context) error {
+	modTime, err := p.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	// 加载失败时同样记录本次尝试的修改时间，Watch 仅在文件再次变更时重试
+	p.modTime.Store(&modTime)
+
+	cert, err := tls.LoadX509KeyPair(p.opts.CertFile, p.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load tls cert %s: %w", p.opts.CertFile, err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parse tls cert %s: %w", p.opts.CertFile, err)
+	}
+	cert.Leaf = leaf
+
+	c := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   p.clientAuth,
+	}
+
+	if p.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(p.opts.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in tls client ca file %s", p.opts.ClientCAFile)
+		}
+		c.ClientCAs = pool
+	}
+
+	p.config.Store(c)
+
+	CertificateExpiry.Record(ctx, float64(leaf.NotAfter.Unix()), otelmetric.WithAttributes(
+		attribute.String("tls.certificate.file", p.opts.CertFile),
+	))
+
+	return nil
+}
+
+// latestModTime 返回证书相关文件中最晚的修改时间；os.Stat 会跟随符号链接，可感知 Kubernetes Secret 的更新。
+func (p *Provider) latestModTime() (latest time.Time, err error) {
+	for _, filename := range []string{p.opts.CertFile, p.opts.KeyFile, p.opts.ClientCAFile} {
+		if filename == "" {
+			continue
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			return latest, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// Watch 定期检查证书文件的修改时间，变更时重新加载，直至 ctx 结束或调用 Close。
+//
+// 信号不在此处理，由服务的 Reload 在信号策略触发重载时调用 Provider.Reload。
+func (p *Provider) Watch(ctx context.Context) {
+	l := logr.FromContext(ctx)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+			modTime, err := p.latestModTime()
+			if err != nil || !modTime.After(*p.modTime.Load()) {
+				continue
+			}
+		}
+
+		if err := p.Reload(ctx); err != nil {
+			l.Warn(fmt.Errorf("reload tls cert failed: %w", err))
+			continue
+		}
+
+		l.Info("tls cert %s reloaded", p.opts.CertFile)
+	}
+}
+
+// Close 停止 Watch。
+func (p *Provider) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
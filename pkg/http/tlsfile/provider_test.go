@@ -0,0 +1,126 @@
+package tlsfile
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	opts := Options{
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+	}
+
+	writeCert(t, opts, "a.example.com")
+
+	p := testingv2.MustValue(t, func() (*Provider, error) {
+		return New(context.Background(), opts)
+	})
+
+	t.Run("加载证书", func(t *testing.T) {
+		c := p.TLSConfig()
+
+		cert := testingv2.MustValue(t, func() (*tls.Certificate, error) {
+			return c.GetCertificate(&tls.ClientHelloInfo{})
+		})
+
+		testingv2.Then(t, "握手使用证书文件中的证书，默认不校验客户端证书",
+			testingv2.Expect(cert.Leaf.Subject.CommonName, testingv2.Equal("a.example.com")),
+			testingv2.Expect(p.config.Load().ClientAuth == tls.NoClientCert, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("重新加载证书", func(t *testing.T) {
+		writeCert(t, opts, "b.example.com")
+
+		testingv2.Must(t, func() error {
+			return p.Reload(context.Background())
+		})
+
+		cert := testingv2.MustValue(t, func() (*tls.Certificate, error) {
+			return p.TLSConfig().GetCertificate(&tls.ClientHelloInfo{})
+		})
+
+		testingv2.Then(t, "后续握手使用新证书",
+			testingv2.Expect(cert.Leaf.Subject.CommonName, testingv2.Equal("b.example.com")),
+		)
+	})
+
+	t.Run("加载失败时保留原证书", func(t *testing.T) {
+		testingv2.Must(t, func() error {
+			return os.WriteFile(opts.KeyFile, []byte("invalid"), 0o600)
+		})
+
+		err := p.Reload(context.Background())
+
+		cert := testingv2.MustValue(t, func() (*tls.Certificate, error) {
+			return p.TLSConfig().GetCertificate(&tls.ClientHelloInfo{})
+		})
+
+		modTime := testingv2.MustValue(t, p.latestModTime)
+
+		testingv2.Then(t, "返回错误且证书不变，记录本次尝试的修改时间以免 Watch 反复重试",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+			testingv2.Expect(cert.Leaf.Subject.CommonName, testingv2.Equal("b.example.com")),
+			testingv2.Expect(p.modTime.Load().Equal(modTime), testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("客户端证书校验模式", func(t *testing.T) {
+		_, err := New(context.Background(), Options{
+			CertFile:   opts.CertFile,
+			KeyFile:    opts.KeyFile,
+			ClientAuth: ClientAuthRequireAndVerify,
+		})
+
+		testingv2.Then(t, "校验客户端证书时需要配置客户端 CA",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+}
+
+func writeCert(t *testing.T, opts Options, commonName string) {
+	t.Helper()
+
+	key := testingv2.MustValue(t, func() (*ecdsa.PrivateKey, error) {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	})
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der := testingv2.MustValue(t, func() ([]byte, error) {
+		return x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	})
+
+	keyDer := testingv2.MustValue(t, func() ([]byte, error) {
+		return x509.MarshalPKCS8PrivateKey(key)
+	})
+
+	testingv2.Must(t, func() error {
+		return os.WriteFile(opts.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	})
+	testingv2.Must(t, func() error {
+		return os.WriteFile(opts.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0o600)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"io/fs"
 	"mime"
+	"net"
 	"net/http"
 	"os"
 	"path"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/innoai-tech/infra/pkg/http/basehref"
 	"github.com/innoai-tech/infra/pkg/http/compress"
+	"github.com/innoai-tech/infra/pkg/http/tlsfile"
 	"github.com/innoai-tech/infra/pkg/http/webapp/appconfig"
 )
 
@@ -47,10 +49,22 @@ type Server struct {
 	Root string `flag:",omitzero"`
 	// Addr Webapp 监听地址
 	Addr string `flag:",omitzero,expose=http"`
+	// TLSCertFile TLS 证书文件，文件变更或触发重载时重新加载
+	TLSCertFile string `flag:",omitzero,volume=secret"`
+	// TLSKeyFile TLS 私钥文件
+	TLSKeyFile string `flag:",omitzero,volume=secret"`
+	// TLSClientCAFile 校验客户端证书的 CA 文件
+	TLSClientCAFile string `flag:",omitzero,volume=secret"`
+	// TLSClientAuth 客户端证书校验模式：none、request、require、verify-if-given、require-and-verify，默认配置了 TLSClientCAFile 时为 require-and-verify
+	TLSClientAuth string `flag:",omitzero"`
 
 	fs fs.FS
 
-	svc *http.Server
+	svc     *http.Server
+	tlsFile *tlsfile.Provider
+
+	ready    sync.WaitGroup
+	readyErr error
 }
 
 // BindFS 绑定一个自定义文件系统作为静态资源来源。
@@ -66,6 +80,9 @@ func (s *Server) SetDefaults() {
 
 	if s.Addr == "" {
 		s.Addr = ":80"
+		if s.TLSCertFile != "" {
+			s.Addr = ":443"
+		}
 	}
 
 	if s.Env == "" {
@@ -86,6 +103,19 @@ func (s *Server) Init(ctx context.Context) error {
 		}
 	}
 
+	if s.TLSCertFile != "" {
+		p, err := tlsfile.New(ctx, tlsfile.Options{
+			CertFile:     s.TLSCertFile,
+			KeyFile:      s.TLSKeyFile,
+			ClientCAFile: s.TLSClientCAFile,
+			ClientAuth:   tlsfile.ClientAuth(s.TLSClientAuth),
+		})
+		if err != nil {
+			return err
+		}
+		s.tlsFile = p
+	}
+
 	ac := appconfig.ParseAppConfig(s.Config)
 	ac.LoadFromEnviron(os.Environ())
 
@@ -103,6 +133,9 @@ func (s *Server) Init(ctx context.Context) error {
 		),
 	}
 
+	// 等待监听就绪
+	s.ready.Add(1)
+
 	return nil
 }
 
@@ -115,12 +148,64 @@ func (s *Server) Serve(ctx context.Context) error {
 	if s.Root != "" {
 		l = l.WithValues("staticRoot", s.Root)
 	}
+
+	ln, err := net.Listen("tcp", s.svc.Addr)
+	if err != nil {
+		s.readyErr = err
+		s.ready.Done()
+		return err
+	}
+	defer ln.Close()
+
 	l.Info("serve on %s (%s/%s)", s.svc.Addr, runtime.GOOS, runtime.GOARCH)
-	return s.svc.ListenAndServe()
+
+	s.ready.Done()
+
+	if s.tlsFile != nil {
+		go s.tlsFile.Watch(ctx)
+
+		s.svc.TLSConfig = s.tlsFile.TLSConfig()
+		return s.svc.ServeTLS(ln, "", "")
+	}
+
+	return s.svc.Serve(ln)
+}
+
+// WaitReady 等待服务监听就绪；监听失败时返回对应错误。
+func (s *Server) WaitReady(ctx context.Context) error {
+	if s.svc == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.ready.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return s.readyErr
+	}
+}
+
+// Reload 重新加载 TLS 证书文件，由信号策略触发重载时调用，例如 configuration.ReloadOnHangup()。
+//
+// 加载失败时继续使用之前的证书。
+func (s *Server) Reload(ctx context.Context) error {
+	if s.tlsFile == nil {
+		return nil
+	}
+	return s.tlsFile.Reload(ctx)
 }
 
 // Shutdown 优雅关闭 HTTP 服务。
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.tlsFile != nil {
+		s.tlsFile.Close()
+	}
 	return s.svc.Shutdown(ctx)
 }
 
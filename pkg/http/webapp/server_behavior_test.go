@@ -153,6 +153,54 @@ func TestServerBindFSInitRuntimeDocAndShutdown(t *testing.T) {
 	)
 }
 
+func TestServerWaitReady(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	occupied := &Server{Addr: ln.Addr().String()}
+	occupied.BindFS(makeTestFS(map[string]string{"index.html": "ok"}))
+
+	Must(t, func() error {
+		return occupied.Init(context.Background())
+	})
+
+	serveErr := occupied.Serve(context.Background())
+
+	s := &Server{Addr: "127.0.0.1:0"}
+	s.BindFS(makeTestFS(map[string]string{"index.html": "ok"}))
+
+	Must(t, func() error {
+		return s.Init(context.Background())
+	})
+
+	serveDone := make(chan error, 1)
+	go func() {
+		serveDone <- s.Serve(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	readyErr := s.WaitReady(ctx)
+
+	Must(t, func() error {
+		return s.Shutdown(context.Background())
+	})
+	<-serveDone
+
+	Then(
+		t, "监听成功后就绪，监听失败时 WaitReady 返回监听错误",
+		Expect(readyErr, Equal(error(nil))),
+		Expect(serveErr == nil, Equal(false)),
+		Expect(occupied.WaitReady(ctx), Equal(serveErr)),
+	)
+}
+
 func TestServeFSRootHTML(t *testing.T) {
 	t.Parallel()
 
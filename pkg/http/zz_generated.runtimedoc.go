@@ -12,6 +12,54 @@ func (v *Server) RuntimeDoc(names ...string) ([]string, bool) {
 			return []string{
 				"启用调试模式",
 			}, true
+		case "LogLevelToken":
+			return []string{
+				"访问 /.sys/loglevel 的 Bearer token，设置后无需启用调试模式；请求头 x-log-level-token 与之一致时请求头 x-enable-log-level 同样作用于请求内的全部日志",
+			}, true
+		case "TLSCertFile":
+			return []string{
+				"TLS 证书文件，文件变更或触发重载时重新加载",
+			}, true
+		case "TLSKeyFile":
+			return []string{
+				"TLS 私钥文件",
+			}, true
+		case "TLSClientCAFile":
+			return []string{
+				"校验客户端证书的 CA 文件",
+			}, true
+		case "TLSClientAuth":
+			return []string{
+				"客户端证书校验模式：none、request、require、verify-if-given、require-and-verify，默认配置了 TLSClientCAFile 时为 require-and-verify",
+			}, true
+		case "AuthPublicPaths":
+			return []string{
+				"无需认证的路径，逗号分隔，以 * 结尾时按前缀匹配，默认仅公开健康检查 /；/.sys/ 下的内置接口同样需要认证，需公开时如 /.sys/metrics 显式列出",
+			}, true
+		case "AuthBearerTokens":
+			return []string{
+				"静态 Bearer token，逗号分隔，形如 subject:token",
+			}, true
+		case "AuthJWKSFile":
+			return []string{
+				"校验 JWT 的本地 JWKS 文件",
+			}, true
+		case "AuthJWTAudience":
+			return []string{
+				"要求 JWT 的 aud 声明包含该值，需配置 AuthJWKSFile",
+			}, true
+		case "AuthJWTIssuer":
+			return []string{
+				"要求 JWT 的 iss 声明与该值一致，需配置 AuthJWKSFile",
+			}, true
+		case "AuthHtpasswdFile":
+			return []string{
+				"Basic 认证的 htpasswd 文件",
+			}, true
+		case "AuthClientCert":
+			return []string{
+				"以已校验的 mTLS 客户端证书作为认证主体，需配置 TLSClientCAFile",
+			}, true
 
 		}
 
@@ -2,7 +2,7 @@
 //
 // 它负责：
 //   - 基于应用信息初始化 logger、tracer 与 meter provider
-//   - 将观测对象注入运行时上下文
+//   - 将观测对象与 trace 传播格式注入运行时上下文
 //   - 协调观测生命周期的初始化与关闭
 //
 // 它不负责：
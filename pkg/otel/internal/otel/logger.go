@@ -3,9 +3,14 @@ package otel
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/trace"
@@ -13,8 +18,36 @@ import (
 	"github.com/octohelm/x/logr"
 )
 
+// LevelVar 表示可在运行期间修改的日志级别，零值可直接使用。
+//
+// 除基础级别外，还支持带过期时间的临时级别与按日志记录器名称的覆盖，过期后自动恢复。
+type LevelVar struct {
+	v         atomic.Pointer[logr.Level]
+	temporary atomic.Pointer[LevelOverride]
+	overrides atomic.Pointer[map[string]LevelOverride]
+	mu        sync.Mutex
+}
+
+// Level 返回当前级别；临时级别未过期时返回临时级别，未设置时返回 logr.Level 的零值。
+func (v *LevelVar) Level() (l logr.Level) {
+	if t := v.temporary.Load(); t != nil && t.active(time.Now()) {
+		return t.Level
+	}
+	if p := v.v.Load(); p != nil {
+		return *p
+	}
+	return l
+}
+
+// Set 修改当前级别。
+func (v *LevelVar) Set(l logr.Level) {
+	v.v.Store(&l)
+}
+
 // NewLogger 根据上下文和启用级别创建带 OpenTelemetry 集成的日志记录器。
-func NewLogger(ctx context.Context, levelEnabled logr.Level) logr.Logger {
+//
+// 启用级别在运行期间修改后对已创建的日志记录器立即生效；sampler 为 nil 时不采样。
+func NewLogger(ctx context.Context, levelEnabled *LevelVar, sampler *LogSampler) logr.Logger {
 	return &logger{
 		spanContext: spanContext{
 			tracerProvider: TracerProviderContext.From(ctx),
@@ -23,6 +56,7 @@ func NewLogger(ctx context.Context, levelEnabled logr.Level) logr.Logger {
 			ctx:            ctx,
 			loggerProvider: LoggerProviderContext.From(ctx),
 			enabled:        levelEnabled,
+			sampler:        sampler,
 		},
 	}
 }
@@ -54,10 +88,12 @@ func (t *logger) Start(ctx context.Context, name string, keyAndValues ...any) (c
 		parentID = parentSpan.SpanID()
 	}
 
-	spanCtx, c := t.spanContext.Start(ctx, name)
+	keyValues := normalizeKeyValues(keyAndValues)
+
+	spanCtx, c := t.spanContext.Start(ctx, name, keyValues...)
 
 	lgr := &logger{
-		keyValues:     append(t.keyValues, normalizeKeyValues(keyAndValues)...),
+		keyValues:     append(t.keyValues, keyValues...),
 		spanContext:   spanCtx,
 		loggerContext: t.loggerContext.Start(c, name, parentID),
 	}
@@ -109,7 +145,9 @@ func (t *logger) Error(err error) {
 type loggerContext struct {
 	ctx            context.Context
 	loggerProvider log.LoggerProvider
-	enabled        logr.Level
+	enabled        *LevelVar
+	sampler        *LogSampler
+	name           string
 	startedAt      time.Time
 	parentID       trace.SpanID
 	logger         log.Logger
@@ -127,23 +165,32 @@ func (lc *loggerContext) getLogger() log.Logger {
 }
 
 func (lc *loggerContext) emit(level logr.Level, msg fmt.Stringer, keyValues []attribute.KeyValue) {
-	var rec log.Record
+	var source Source
+	if level <= logr.WarnLevel || lc.sampler != nil {
+		source = GetSource(3)
+	}
+
+	if lc.sampler != nil && !lc.sampler.Allow(level, messageTemplate(msg), source.Function) {
+		return
+	}
 
-	switch level {
-	case logr.DebugLevel:
-		rec.SetSeverity(log.SeverityDebug)
-	case logr.InfoLevel:
-		rec.SetSeverity(log.SeverityInfo)
-	case logr.WarnLevel:
-		rec.AddAttributes(GetSource(3).AsKeyValues()...)
-		rec.SetSeverity(log.SeverityWarn)
-	case logr.ErrorLevel:
-		rec.AddAttributes(GetSource(3).AsKeyValues()...)
-		rec.SetSeverity(log.SeverityError)
+	var sourceKeyValues []attribute.KeyValue
+	if level <= logr.WarnLevel {
+		sourceKeyValues = source.AsKeyValues()
 	}
 
+	lc.record(level, sourceKeyValues, msg, keyValues)
+}
+
+// record 输出日志记录，属性与消息经 DefaultRedactor 脱敏；sourceKeyValues 为日志的源码位置。
+func (lc *loggerContext) record(level logr.Level, sourceKeyValues []attribute.KeyValue, msg fmt.Stringer, keyValues []attribute.KeyValue) {
+	var rec log.Record
+
+	rec.SetSeverity(severityOf(level))
+	rec.AddAttributes(sourceKeyValues...)
+
 	if len(keyValues) > 0 {
-		rec.AddAttributes(keyValues...)
+		rec.AddAttributes(DefaultRedactor.RedactKeyValues(keyValues)...)
 	}
 
 	if !lc.startedAt.IsZero() {
@@ -154,14 +201,63 @@ func (lc *loggerContext) emit(level logr.Level, msg fmt.Stringer, keyValues []at
 		rec.AddAttributes(attribute.String("trace.parent.span.id", lc.parentID.String()))
 	}
 
+	// 输出所在 span 的采样结果，便于判断该日志能否关联到已上报的 trace
+	if sc := trace.SpanContextFromContext(lc.ctx); sc.IsValid() {
+		rec.AddAttributes(attribute.Bool("trace.sampled", sc.IsSampled()))
+	}
+
+	// 将传播而来的 baggage 作为日志属性输出
+	rec.AddAttributes(baggageKeyValues(baggage.FromContext(lc.ctx))...)
+
 	rec.SetTimestamp(time.Now())
-	rec.SetBody(attribute.StringValue(msg.String()))
+	rec.SetBody(attribute.StringValue(DefaultRedactor.Redact("", msg.String())))
 
 	lc.getLogger().Emit(lc.ctx, rec)
 }
 
+const (
+	// maxBaggageKeyValues 每条日志输出的 baggage 属性上限
+	maxBaggageKeyValues = 8
+	// maxBaggageValueLen baggage 属性值的长度上限，超出部分截断
+	maxBaggageValueLen = 128
+)
+
+// baggageKeyValues 将 baggage 转为 baggage.* 日志属性。
+//
+// baggage 来自上游请求，按键名排序后只取前 maxBaggageKeyValues 个，值截断至 maxBaggageValueLen 字节，避免日志随之无界增长。
+func baggageKeyValues(b baggage.Baggage) []attribute.KeyValue {
+	members := b.Members()
+	if len(members) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(members, func(a, b baggage.Member) int {
+		return strings.Compare(a.Key(), b.Key())
+	})
+
+	keyValues := make([]attribute.KeyValue, 0, min(len(members), maxBaggageKeyValues))
+
+	for _, m := range members[:min(len(members), maxBaggageKeyValues)] {
+		v := m.Value()
+		if len(v) > maxBaggageValueLen {
+			v = strings.ToValidUTF8(v[:maxBaggageValueLen], "")
+		}
+		keyValues = append(keyValues, DefaultRedactor.RedactKeyValue(attribute.String("baggage."+m.Key(), v)))
+	}
+
+	return keyValues
+}
+
+// enabledLevel 返回生效的级别：上下文中的覆盖优先，其次为按名称的覆盖，最后为全局级别。
+func (l *loggerContext) enabledLevel() logr.Level {
+	if lvl, ok := LogLevelContext.MayFrom(l.ctx); ok {
+		return lvl
+	}
+	return l.enabled.LevelFor(l.name)
+}
+
 func (l *loggerContext) info(level logr.Level, msg fmt.Stringer, keyValues []attribute.KeyValue) {
-	if level > l.enabled {
+	if level > l.enabledLevel() {
 		return
 	}
 
@@ -169,7 +265,7 @@ func (l *loggerContext) info(level logr.Level, msg fmt.Stringer, keyValues []att
 }
 
 func (l *loggerContext) error(level logr.Level, err error, keyValues []attribute.KeyValue, postDo func(err error)) {
-	if level > l.enabled {
+	if level > l.enabledLevel() {
 		return
 	}
 
@@ -177,13 +273,14 @@ func (l *loggerContext) error(level logr.Level, err error, keyValues []attribute
 		return
 	}
 
-	l.emit(level, sprintf("%s", err), keyValues)
+	l.emit(level, &errorMessage{err: err}, keyValues)
 
 	postDo(err)
 }
 
 func (l loggerContext) Start(ctx context.Context, name string, parentID trace.SpanID) loggerContext {
 	l.ctx = ctx
+	l.name = name
 	l.startedAt = time.Now()
 	l.parentID = parentID
 	lp, ok := LoggerProviderContext.MayFrom(l.ctx)
@@ -201,17 +298,27 @@ type spanContext struct {
 	span           trace.Span
 }
 
-func (c spanContext) Start(ctx context.Context, spanName string) (spanContext, context.Context) {
+// Start 创建 span，attrs 作为起始属性供采样器（如按 http.route 的采样规则）使用。
+func (c spanContext) Start(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (spanContext, context.Context) {
 	tp, ok := TracerProviderContext.MayFrom(ctx)
 	if !ok {
 		tp = c.tracerProvider
 	}
-	cc, span := tp.Tracer("").Start(ctx, c.name, trace.WithTimestamp(time.Now()))
+	cc, span := tp.Tracer("").Start(ctx, c.name, trace.WithTimestamp(time.Now()), trace.WithAttributes(attrs...))
 	c.span = span
 	c.name = spanName
 	return c, cc
 }
 
+// errorMessage 为 Warn、Error 日志的消息，采样时按错误消息分组。
+type errorMessage struct {
+	err error
+}
+
+func (m *errorMessage) String() string {
+	return m.err.Error()
+}
+
 func sprintf(format string, args ...any) fmt.Stringer {
 	return &printer{format: format, args: args}
 }
@@ -0,0 +1,139 @@
+package otel
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	contextx "github.com/octohelm/x/context"
+	"github.com/octohelm/x/logr"
+)
+
+// LogLevelContext 是用于上下文注入的日志级别覆盖，注入后该上下文中启动的日志记录器均使用该级别。
+var LogLevelContext = contextx.New[logr.Level]()
+
+// ContextWithLogLevel 返回覆盖了日志级别的上下文。
+func ContextWithLogLevel(ctx context.Context, level logr.Level) context.Context {
+	return LogLevelContext.Inject(ctx, level)
+}
+
+// LevelOverride 表示带过期时间的日志级别覆盖，ExpiresAt 为零值时不过期。
+type LevelOverride struct {
+	Level     logr.Level
+	ExpiresAt time.Time
+}
+
+func (o *LevelOverride) active(now time.Time) bool {
+	return o.ExpiresAt.IsZero() || now.Before(o.ExpiresAt)
+}
+
+func newLevelOverride(l logr.Level, ttl time.Duration) LevelOverride {
+	o := LevelOverride{Level: l}
+	if ttl > 0 {
+		o.ExpiresAt = time.Now().Add(ttl)
+	}
+	return o
+}
+
+// Base 返回基础级别，不含临时级别。
+func (v *LevelVar) Base() (l logr.Level) {
+	if p := v.v.Load(); p != nil {
+		return *p
+	}
+	return l
+}
+
+// SetTemporary 设置临时级别，ttl 到期后恢复为基础级别。
+func (v *LevelVar) SetTemporary(l logr.Level, ttl time.Duration) {
+	o := newLevelOverride(l, ttl)
+	v.temporary.Store(&o)
+}
+
+// ClearTemporary 清除临时级别。
+func (v *LevelVar) ClearTemporary() {
+	v.temporary.Store(nil)
+}
+
+// Temporary 返回未过期的临时级别。
+func (v *LevelVar) Temporary() (LevelOverride, bool) {
+	if t := v.temporary.Load(); t != nil && t.active(time.Now()) {
+		return *t, true
+	}
+	return LevelOverride{}, false
+}
+
+// LevelFor 返回指定名称日志记录器的级别，无未过期的覆盖时返回 Level()。
+func (v *LevelVar) LevelFor(name string) logr.Level {
+	if name != "" {
+		if m := v.overrides.Load(); m != nil {
+			if o, ok := (*m)[name]; ok && o.active(time.Now()) {
+				return o.Level
+			}
+		}
+	}
+	return v.Level()
+}
+
+// SetOverride 覆盖指定名称日志记录器的级别，ttl 大于 0 时到期后自动恢复。
+func (v *LevelVar) SetOverride(name string, l logr.Level, ttl time.Duration) {
+	v.updateOverrides(func(m map[string]LevelOverride) {
+		m[name] = newLevelOverride(l, ttl)
+	})
+}
+
+// DeleteOverride 移除指定名称日志记录器的级别覆盖。
+func (v *LevelVar) DeleteOverride(name string) {
+	v.updateOverrides(func(m map[string]LevelOverride) {
+		delete(m, name)
+	})
+}
+
+// Overrides 返回全部未过期的级别覆盖。
+func (v *LevelVar) Overrides() map[string]LevelOverride {
+	overrides := map[string]LevelOverride{}
+
+	if m := v.overrides.Load(); m != nil {
+		now := time.Now()
+		for name, o := range *m {
+			if o.active(now) {
+				overrides[name] = o
+			}
+		}
+	}
+
+	return overrides
+}
+
+// updateOverrides 以写时复制方式更新覆盖，并顺带清理已过期的覆盖，使读取无需加锁。
+func (v *LevelVar) updateOverrides(update func(m map[string]LevelOverride)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	m := map[string]LevelOverride{}
+	if current := v.overrides.Load(); current != nil {
+		m = maps.Clone(*current)
+	}
+
+	now := time.Now()
+	maps.DeleteFunc(m, func(name string, o LevelOverride) bool {
+		return !o.active(now)
+	})
+
+	update(m)
+
+	v.overrides.Store(&m)
+}
+
+// LogLevelOf 将 logr.Level 转换为 LogLevel。
+func LogLevelOf(l logr.Level) LogLevel {
+	switch l {
+	case logr.ErrorLevel:
+		return ErrorLevel
+	case logr.WarnLevel:
+		return WarnLevel
+	case logr.DebugLevel:
+		return DebugLevel
+	default:
+		return InfoLevel
+	}
+}
@@ -35,3 +35,16 @@ const (
 	LogFormatJSON LogFormat = "json"
 	LogFormatText LogFormat = "text"
 )
+
+// LogDropPolicy 表示异步日志队列满时的处理策略。
+// +gengo:enum
+type LogDropPolicy string
+
+const (
+	// LogDropPolicyDropDebugFirst 优先丢弃 debug 日志，无 debug 日志可丢时丢弃新日志。
+	LogDropPolicyDropDebugFirst LogDropPolicy = "drop-debug-first"
+	// LogDropPolicyDropNewest 丢弃新日志。
+	LogDropPolicyDropNewest LogDropPolicy = "drop-newest"
+	// LogDropPolicyBlock 阻塞写入直至队列有空位。
+	LogDropPolicyBlock LogDropPolicy = "block"
+)
@@ -0,0 +1,141 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+
+	"github.com/octohelm/x/logr"
+)
+
+// LogSampler 按 (级别, 消息模板, source.func) 对日志采样：每个统计周期内保留前 first 条，
+// 之后每 thereafter 条保留 1 条（thereafter 不大于 0 时全部抑制）；error 日志仅在 sampleErrors 时参与采样。
+type LogSampler struct {
+	first        int
+	thereafter   int
+	sampleErrors bool
+
+	mu       sync.Mutex
+	counters map[logSampleKey]*logSampleCounter
+}
+
+// NewLogSampler 创建日志采样器。
+func NewLogSampler(first int, thereafter int, sampleErrors bool) *LogSampler {
+	return &LogSampler{
+		first:        first,
+		thereafter:   thereafter,
+		sampleErrors: sampleErrors,
+		counters:     map[logSampleKey]*logSampleCounter{},
+	}
+}
+
+type logSampleKey struct {
+	level    logr.Level
+	template string
+	source   string
+}
+
+type logSampleCounter struct {
+	seen       int
+	suppressed int
+}
+
+// Allow 返回该日志是否应输出。
+func (s *LogSampler) Allow(level logr.Level, template string, sourceFunc string) bool {
+	if level == logr.ErrorLevel && !s.sampleErrors {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := logSampleKey{level: level, template: template, source: sourceFunc}
+
+	c, ok := s.counters[k]
+	if !ok {
+		c = &logSampleCounter{}
+		s.counters[k] = c
+	}
+
+	c.seen++
+
+	if c.seen <= s.first {
+		return true
+	}
+
+	if s.thereafter > 0 && (c.seen-s.first)%s.thereafter == 0 {
+		return true
+	}
+
+	c.suppressed++
+
+	return false
+}
+
+// Flush 结束当前统计周期，为每组被抑制的日志输出一条汇总记录；
+// 汇总记录不参与采样，但与普通日志一样受 levelEnabled 控制并经脱敏输出。
+func (s *LogSampler) Flush(ctx context.Context, lp LoggerProvider, levelEnabled *LevelVar, interval time.Duration) {
+	s.mu.Lock()
+	counters := s.counters
+	s.counters = map[logSampleKey]*logSampleCounter{}
+	s.mu.Unlock()
+
+	keys := slices.SortedFunc(maps.Keys(counters), func(a, b logSampleKey) int {
+		return strings.Compare(a.template+a.source, b.template+b.source)
+	})
+
+	lc := &loggerContext{
+		ctx:            ctx,
+		loggerProvider: lp,
+		enabled:        levelEnabled,
+	}
+
+	for _, k := range keys {
+		c := counters[k]
+		if c.suppressed == 0 || k.level > lc.enabledLevel() {
+			continue
+		}
+
+		lc.record(
+			k.level,
+			[]attribute.KeyValue{attribute.String("source.func", k.source)},
+			sprintf("%d similar logs suppressed in %s", c.suppressed, interval),
+			[]attribute.KeyValue{
+				attribute.String("log.sampled.template", k.template),
+				attribute.Int("log.sampled.seen", c.seen),
+				attribute.Int("log.sampled.suppressed", c.suppressed),
+			},
+		)
+	}
+}
+
+func severityOf(level logr.Level) log.Severity {
+	switch level {
+	case logr.DebugLevel:
+		return log.SeverityDebug
+	case logr.InfoLevel:
+		return log.SeverityInfo
+	case logr.WarnLevel:
+		return log.SeverityWarn
+	default:
+		return log.SeverityError
+	}
+}
+
+// messageTemplate 返回消息的格式模板，使参数不同的同类日志归为一组；Warn、Error 日志按错误消息分组。
+func messageTemplate(msg fmt.Stringer) string {
+	switch m := msg.(type) {
+	case *printer:
+		return m.format
+	case *errorMessage:
+		return m.err.Error()
+	}
+	return msg.String()
+}
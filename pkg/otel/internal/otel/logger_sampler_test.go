@@ -0,0 +1,132 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/octohelm/x/logr"
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestLogSampler(t *testing.T) {
+	t.Run("保留前 N 条，之后每 M 条保留 1 条", func(t *testing.T) {
+		s := NewLogSampler(2, 3, false)
+
+		allowed := make([]bool, 0)
+		for range 8 {
+			allowed = append(allowed, s.Allow(logr.InfoLevel, "success", "main.handle"))
+		}
+
+		testingv2.Then(t, "第 1、2 条及之后每第 3 条输出",
+			testingv2.Expect(allowed, testingv2.Equal([]bool{true, true, false, false, true, false, false, true})),
+			testingv2.Expect(s.counters[logSampleKey{level: logr.InfoLevel, template: "success", source: "main.handle"}].suppressed, testingv2.Equal(4)),
+		)
+	})
+
+	t.Run("按模板与来源分组", func(t *testing.T) {
+		s := NewLogSampler(1, 0, false)
+
+		testingv2.Then(t, "不同模板或来源互不影响",
+			testingv2.Expect(s.Allow(logr.InfoLevel, "a", "f"), testingv2.Equal(true)),
+			testingv2.Expect(s.Allow(logr.InfoLevel, "a", "f"), testingv2.Equal(false)),
+			testingv2.Expect(s.Allow(logr.InfoLevel, "b", "f"), testingv2.Equal(true)),
+			testingv2.Expect(s.Allow(logr.InfoLevel, "a", "g"), testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("error 日志默认不采样", func(t *testing.T) {
+		s := NewLogSampler(1, 0, false)
+		allowErrors := NewLogSampler(1, 0, true)
+
+		testingv2.Then(t, "仅 sampleErrors 时抑制 error 日志",
+			testingv2.Expect(s.Allow(logr.ErrorLevel, "failed", "f"), testingv2.Equal(true)),
+			testingv2.Expect(s.Allow(logr.ErrorLevel, "failed", "f"), testingv2.Equal(true)),
+			testingv2.Expect(allowErrors.Allow(logr.ErrorLevel, "failed", "f"), testingv2.Equal(true)),
+			testingv2.Expect(allowErrors.Allow(logr.ErrorLevel, "failed", "f"), testingv2.Equal(false)),
+		)
+	})
+
+	t.Run("Warn、Error 日志按错误消息分组", func(t *testing.T) {
+		testingv2.Then(t, "模板取错误消息而非统一的 %s",
+			testingv2.Expect(messageTemplate(&errorMessage{err: errors.New("dial failed")}), testingv2.Equal("dial failed")),
+			testingv2.Expect(messageTemplate(sprintf("user %s login", "a")), testingv2.Equal("user %s login")),
+		)
+	})
+
+	t.Run("汇总记录受级别控制并脱敏", func(t *testing.T) {
+		p := &recordingProcessor{}
+		lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(p))
+
+		levelEnabled := &LevelVar{}
+		levelEnabled.Set(logr.WarnLevel)
+
+		s := NewLogSampler(0, 0, false)
+		s.Allow(logr.WarnLevel, "login failed for alice@example.com", "main.login")
+		s.Allow(logr.InfoLevel, "success", "main.handle")
+
+		s.Flush(context.Background(), lp, levelEnabled, time.Second)
+
+		testingv2.Then(t, "仅输出启用级别内的汇总，模板中的敏感值被替换",
+			testingv2.Expect(p.bodies(), testingv2.Equal([]string{"1 similar logs suppressed in 1s"})),
+			testingv2.Expect(p.attr(0, "log.sampled.template"), testingv2.Equal("login failed for "+RedactedValue)),
+			testingv2.Expect(p.attr(0, "source.func"), testingv2.Equal("main.login")),
+		)
+	})
+}
+
+type recordingProcessor struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (p *recordingProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.records = append(p.records, record.Clone())
+	return nil
+}
+
+func (p *recordingProcessor) Enabled(ctx context.Context, param sdklog.EnabledParameters) bool {
+	return true
+}
+
+func (p *recordingProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *recordingProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (p *recordingProcessor) bodies() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bodies := make([]string, 0, len(p.records))
+	for _, r := range p.records {
+		bodies = append(bodies, r.Body().AsString())
+	}
+	return bodies
+}
+
+func (p *recordingProcessor) attr(i int, key string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if i >= len(p.records) {
+		return ""
+	}
+
+	for attr := range p.records[i].WalkAttributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestBaggageKeyValues(t *testing.T) {
+	members := make([]baggage.Member, 0)
+	for i := range maxBaggageKeyValues + 2 {
+		members = append(members, testingv2.MustValue(t, func() (baggage.Member, error) {
+			return baggage.NewMemberRaw(fmt.Sprintf("k%02d", i), strings.Repeat("v", maxBaggageValueLen+10))
+		}))
+	}
+
+	b := testingv2.MustValue(t, func() (baggage.Baggage, error) {
+		return baggage.New(members...)
+	})
+
+	keyValues := baggageKeyValues(b)
+
+	testingv2.Then(t, "按键名取前若干个成员，值超长时截断",
+		testingv2.Expect(len(keyValues), testingv2.Equal(maxBaggageKeyValues)),
+		testingv2.Expect(string(keyValues[0].Key), testingv2.Equal("baggage.k00")),
+		testingv2.Expect(string(keyValues[maxBaggageKeyValues-1].Key), testingv2.Equal(fmt.Sprintf("baggage.k%02d", maxBaggageKeyValues-1))),
+		testingv2.Expect(len(keyValues[0].Value.AsString()), testingv2.Equal(maxBaggageValueLen)),
+	)
+
+	t.Run("敏感键", func(t *testing.T) {
+		b := testingv2.MustValue(t, func() (baggage.Baggage, error) {
+			m, err := baggage.NewMemberRaw("token", "abc")
+			if err != nil {
+				return baggage.Baggage{}, err
+			}
+			return baggage.New(m)
+		})
+
+		testingv2.Then(t, "值被脱敏",
+			testingv2.Expect(baggageKeyValues(b)[0].Value.AsString(), testingv2.Equal(RedactedValue)),
+		)
+	})
+}
@@ -0,0 +1,49 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/propagation"
+
+	contextx "github.com/octohelm/x/context"
+)
+
+// TextMapPropagator 是对 OpenTelemetry propagation.TextMapPropagator 的类型别名。
+type TextMapPropagator = propagation.TextMapPropagator
+
+// DefaultPropagators 为默认启用的传播格式：W3C TraceContext、W3C Baggage 与 B3。
+const DefaultPropagators = "tracecontext,baggage,b3"
+
+// PropagatorContext 是用于上下文注入的 TextMapPropagator 上下文键，未注入时使用默认组合。
+var PropagatorContext = contextx.New[TextMapPropagator](contextx.WithDefaultsFunc(func() TextMapPropagator {
+	p, _ := ParsePropagators(DefaultPropagators)
+	return p
+}))
+
+// ParsePropagators 解析以逗号分隔的传播格式列表，支持 tracecontext、baggage、b3、b3single 与 none。
+func ParsePropagators(s string) (TextMapPropagator, error) {
+	propagators := make([]propagation.TextMapPropagator, 0)
+
+	for name := range strings.SplitSeq(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "none":
+			return propagation.NewCompositeTextMapPropagator(), nil
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			// 与之前一致，注入 X-B3-* 多头格式
+			propagators = append(propagators, b3.New())
+		case "b3single":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		default:
+			return nil, fmt.Errorf("unsupported propagator %q, should be one of tracecontext, baggage, b3, b3single, none", name)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
@@ -0,0 +1,282 @@
+package otel
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactedValue 为敏感值被替换后的内容。
+const RedactedValue = "******"
+
+// RedactRule 为自定义脱敏规则，返回替换后的值及是否命中。
+type RedactRule func(key string, value string) (string, bool)
+
+// Redactor 按键名与值的模式对日志、span 属性及 HTTP 的 URL、请求头脱敏，并发安全。
+//
+// 规则以写时复制的快照保存，脱敏时无需加锁。
+type Redactor struct {
+	mu    sync.Mutex
+	rules atomic.Pointer[redactRules]
+}
+
+type redactRules struct {
+	keys   []*regexp.Regexp
+	values []*regexp.Regexp
+	rules  []RedactRule
+}
+
+// NewRedactor 创建带默认键名与值模式的 Redactor。
+//
+// 键名默认按单词边界匹配 authorization、cookie、password、secret、token、api-key，如 access_token、x-api-key，
+// 但不匹配 max_tokens；值默认匹配 JWT、Bearer token、邮箱。银行卡号需经 AddRule(RedactCardNumbers) 启用。
+func NewRedactor() *Redactor {
+	r := &Redactor{}
+
+	r.AddKeyPattern(regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:authorization|cookie|passw(?:or)?d|secret|token|api[-_.]?key)(?:$|[^a-z0-9])`))
+
+	r.AddValuePattern(regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`))
+	r.AddValuePattern(regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`))
+	r.AddValuePattern(regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`))
+
+	return r
+}
+
+var cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// RedactCardNumbers 替换通过 Luhn 校验的 13 至 19 位数字，避免误伤普通的长数字 ID。
+func RedactCardNumbers(key string, value string) (string, bool) {
+	matched := false
+
+	value = cardNumberPattern.ReplaceAllStringFunc(value, func(s string) string {
+		if !luhnValid(s) {
+			return s
+		}
+		matched = true
+		return RedactedValue
+	})
+
+	return value, matched
+}
+
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// DefaultRedactor 为全局脱敏注册表。
+var DefaultRedactor = NewRedactor()
+
+// update 在规则快照的副本上修改后替换快照。
+func (r *Redactor) update(fn func(rules *redactRules)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := &redactRules{}
+	if current := r.rules.Load(); current != nil {
+		next.keys = slices.Clone(current.keys)
+		next.values = slices.Clone(current.values)
+		next.rules = slices.Clone(current.rules)
+	}
+
+	fn(next)
+
+	r.rules.Store(next)
+}
+
+func (r *Redactor) load() *redactRules {
+	if rules := r.rules.Load(); rules != nil {
+		return rules
+	}
+	return &redactRules{}
+}
+
+// AddKeyPattern 注册键名模式，命中的键对应的值整体替换为 RedactedValue。
+func (r *Redactor) AddKeyPattern(pattern *regexp.Regexp) {
+	r.update(func(rules *redactRules) {
+		rules.keys = append(rules.keys, pattern)
+	})
+}
+
+// AddValuePattern 注册值模式，值中命中的片段替换为 RedactedValue。
+func (r *Redactor) AddValuePattern(pattern *regexp.Regexp) {
+	r.update(func(rules *redactRules) {
+		rules.values = append(rules.values, pattern)
+	})
+}
+
+// AddRule 注册自定义规则，在键名与值模式之后依次执行。
+func (r *Redactor) AddRule(rule RedactRule) {
+	r.update(func(rules *redactRules) {
+		rules.rules = append(rules.rules, rule)
+	})
+}
+
+// SensitiveKey 返回键名是否为敏感键。
+func (r *Redactor) SensitiveKey(key string) bool {
+	return r.load().sensitiveKey(key)
+}
+
+func (rules *redactRules) sensitiveKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, p := range rules.keys {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact 返回脱敏后的值；key 可为空，此时仅按值模式与自定义规则处理。
+func (r *Redactor) Redact(key string, value string) string {
+	if value == "" {
+		return value
+	}
+
+	rules := r.load()
+
+	if rules.sensitiveKey(key) {
+		return RedactedValue
+	}
+
+	for _, p := range rules.values {
+		value = p.ReplaceAllString(value, RedactedValue)
+	}
+
+	for _, rule := range rules.rules {
+		if v, ok := rule(key, value); ok {
+			value = v
+		}
+	}
+
+	return value
+}
+
+// RedactKeyValues 对字符串及字符串切片类型的属性脱敏，返回新的切片。
+func (r *Redactor) RedactKeyValues(keyValues []attribute.KeyValue) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, len(keyValues))
+
+	for i, kv := range keyValues {
+		redacted[i] = r.RedactKeyValue(kv)
+	}
+
+	return redacted
+}
+
+// RedactKeyValue 对字符串及字符串切片类型的属性脱敏。
+func (r *Redactor) RedactKeyValue(kv attribute.KeyValue) attribute.KeyValue {
+	key := string(kv.Key)
+
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return attribute.String(key, r.Redact(key, kv.Value.AsString()))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		for i := range values {
+			values[i] = r.Redact(key, values[i])
+		}
+		return attribute.StringSlice(key, values)
+	default:
+		if r.SensitiveKey(key) {
+			return attribute.String(key, RedactedValue)
+		}
+		return kv
+	}
+}
+
+// RedactURL 返回脱敏后的 URL 字符串，不修改 u：敏感的查询参数与密码被替换。
+func (r *Redactor) RedactURL(u *url.URL) string {
+	redacted := *u
+
+	if redacted.User != nil {
+		if _, ok := redacted.User.Password(); ok {
+			redacted.User = url.UserPassword(redacted.User.Username(), RedactedValue)
+		}
+	}
+
+	if redacted.RawQuery != "" {
+		query := u.Query()
+		for key, values := range query {
+			for i := range values {
+				values[i] = r.Redact(key, values[i])
+			}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.String()
+}
+
+// RedactSpanExporter 包装 SpanExporter，导出前对 span 及其事件的属性脱敏。
+func RedactSpanExporter(spanExporter sdktrace.SpanExporter, redactor *Redactor) sdktrace.SpanExporter {
+	return &redactSpanExporter{
+		SpanExporter: spanExporter,
+		redactor:     redactor,
+	}
+}
+
+type redactSpanExporter struct {
+	sdktrace.SpanExporter
+	redactor *Redactor
+}
+
+func (e *redactSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+
+	for i, s := range spans {
+		redacted[i] = &redactedSpan{ReadOnlySpan: s, redactor: e.redactor}
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redactor *Redactor
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.redactor.RedactKeyValues(s.ReadOnlySpan.Attributes())
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+
+	redacted := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Attributes = s.redactor.RedactKeyValues(e.Attributes)
+		redacted[i] = e
+	}
+
+	return redacted
+}
@@ -0,0 +1,288 @@
+package otel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTraceSampler 根据采样策略与按路由的采样规则创建 sdktrace.Sampler。
+//
+// strategy 可选 always、never、ratio:<f>、parentbased_ratio:<f>；
+// rules 形如 "/healthz=0,/api/*=0.1"，按 span 起始属性 http.route 匹配，末尾 * 表示前缀匹配，先匹配者生效。
+//
+// recordDropped 为 true 且策略不为 never 时，未被采样的 span 仍会被记录，以便 KeepSpanProcessor 在结束时保留出错或慢的 span；
+// 仅应在启用了 KeepSpanProcessor 时开启，否则只会增加记录开销。
+func NewTraceSampler(strategy string, rules string, recordDropped bool) (sdktrace.Sampler, error) {
+	base, err := parseSampler(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	routeRules, err := parseTraceSampleRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	recordOnly := recordDropped && strategy != "never"
+
+	if len(routeRules) == 0 && (strategy == "" || strategy == "always" || !recordOnly) {
+		return base, nil
+	}
+
+	return &ruleSampler{
+		base:       base,
+		rules:      routeRules,
+		recordOnly: recordOnly,
+	}, nil
+}
+
+func parseSampler(strategy string) (sdktrace.Sampler, error) {
+	name, value, _ := strings.Cut(strings.TrimSpace(strategy), ":")
+
+	switch name {
+	case "", "always":
+		return sdktrace.AlwaysSample(), nil
+	case "never":
+		return sdktrace.NeverSample(), nil
+	case "ratio", "parentbased_ratio":
+		ratio, err := parseRatio(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trace sampler %q: %w", strategy, err)
+		}
+		if name == "parentbased_ratio" {
+			return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+		}
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	default:
+		return nil, fmt.Errorf("unsupported trace sampler %q, should be one of always, never, ratio:<f>, parentbased_ratio:<f>", strategy)
+	}
+}
+
+func parseRatio(s string) (float64, error) {
+	ratio, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("ratio should be in [0, 1], but got %v", ratio)
+	}
+	return ratio, nil
+}
+
+type traceSampleRule struct {
+	route   string
+	prefix  bool
+	sampler sdktrace.Sampler
+}
+
+func (r *traceSampleRule) match(route string) bool {
+	if r.prefix {
+		return strings.HasPrefix(route, r.route)
+	}
+	return route == r.route
+}
+
+func parseTraceSampleRules(s string) ([]*traceSampleRule, error) {
+	rules := make([]*traceSampleRule, 0)
+
+	for rule := range strings.SplitSeq(s, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		route, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid trace sample rule %q, should be <route>=<ratio>", rule)
+		}
+
+		ratio, err := parseRatio(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trace sample rule %q: %w", rule, err)
+		}
+
+		r := &traceSampleRule{
+			route:   strings.TrimSpace(route),
+			sampler: sdktrace.TraceIDRatioBased(ratio),
+		}
+
+		if prefix, ok := strings.CutSuffix(r.route, "*"); ok {
+			r.route = prefix
+			r.prefix = true
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+type ruleSampler struct {
+	base       sdktrace.Sampler
+	rules      []*traceSampleRule
+	recordOnly bool
+}
+
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	sampler := s.base
+
+	if route, ok := attributeString(p.Attributes, "http.route"); ok {
+		for _, r := range s.rules {
+			if r.match(route) {
+				sampler = r.sampler
+				break
+			}
+		}
+	}
+
+	result := sampler.ShouldSample(p)
+
+	if s.recordOnly && result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+
+	return result
+}
+
+func (s *ruleSampler) Description() string {
+	return fmt.Sprintf("RuleSampler{%s,rules:%d}", s.base.Description(), len(s.rules))
+}
+
+func attributeString(attrs []attribute.KeyValue, key attribute.Key) (string, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit(), true
+		}
+	}
+	return "", false
+}
+
+// KeepSpanProcessor 包装 SpanProcessor，对未被采样但出错（状态为 Error 或 http.response.status_code >= 500，keepErrors 为 true 时）
+// 或耗时不小于 slowThreshold 的 span 标记为已采样后继续上报；slowThreshold 不大于 0 时不按耗时保留。
+//
+// 为避免保留的 span 在链路中成为孤儿，其在本进程内的祖先 span（直至本地根 span）结束时也会被保留；
+// 父 span 来自上游且未被上游采样时，上游部分的链路仍然缺失。
+// 待保留的祖先按链路记录，本地根 span 结束时清除；记录的链路达到 maxKeptTraces 条时全部丢弃，已记录链路的祖先不再保留。
+func KeepSpanProcessor(next sdktrace.SpanProcessor, keepErrors bool, slowThreshold time.Duration) sdktrace.SpanProcessor {
+	return &keepSpanProcessor{
+		SpanProcessor: next,
+		keepErrors:    keepErrors,
+		slowThreshold: slowThreshold,
+		keptChildren:  map[trace.TraceID]map[trace.SpanID]struct{}{},
+	}
+}
+
+// maxKeptTraces 同时记录待保留祖先的链路上限
+const maxKeptTraces = 4096
+
+type keepSpanProcessor struct {
+	sdktrace.SpanProcessor
+	keepErrors    bool
+	slowThreshold time.Duration
+
+	mu sync.Mutex
+	// keptChildren 按链路记录有子 span 被保留、结束时需一同保留的本地 span
+	keptChildren map[trace.TraceID]map[trace.SpanID]struct{}
+}
+
+func (p *keepSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+
+	hasKeptChildren := p.popKeptChildren(s.SpanContext(), isLocalRoot(s.Parent()))
+
+	if hasKeptChildren || p.shouldKeep(s) {
+		p.markKept(s.Parent())
+		p.SpanProcessor.OnEnd(&keptSpan{ReadOnlySpan: s})
+	}
+}
+
+// popKeptChildren 返回 span 是否有子 span 被保留；本地根 span 结束时清除整条链路的记录，晚于其结束的祖先不再保留。
+func (p *keepSpanProcessor) popKeptChildren(sc trace.SpanContext, root bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	spans, ok := p.keptChildren[sc.TraceID()]
+	if !ok {
+		return false
+	}
+
+	_, kept := spans[sc.SpanID()]
+
+	if root {
+		delete(p.keptChildren, sc.TraceID())
+		return kept
+	}
+
+	if kept {
+		delete(spans, sc.SpanID())
+		if len(spans) == 0 {
+			delete(p.keptChildren, sc.TraceID())
+		}
+	}
+
+	return kept
+}
+
+// markKept 标记父 span 需在结束时保留；父 span 来自上游或不存在时忽略。
+func (p *keepSpanProcessor) markKept(parent trace.SpanContext) {
+	if isLocalRoot(parent) || parent.IsSampled() {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	spans, ok := p.keptChildren[parent.TraceID()]
+	if !ok {
+		if len(p.keptChildren) >= maxKeptTraces {
+			// 子 span 晚于本地根 span 结束时记录无法清除，达到上限时整体丢弃
+			clear(p.keptChildren)
+		}
+		spans = map[trace.SpanID]struct{}{}
+		p.keptChildren[parent.TraceID()] = spans
+	}
+
+	spans[parent.SpanID()] = struct{}{}
+}
+
+// isLocalRoot 判断父 span 为 parent 的 span 是否为本进程内的根 span。
+func isLocalRoot(parent trace.SpanContext) bool {
+	return !parent.IsValid() || parent.IsRemote()
+}
+
+func (p *keepSpanProcessor) shouldKeep(s sdktrace.ReadOnlySpan) bool {
+	if p.keepErrors {
+		if s.Status().Code == codes.Error {
+			return true
+		}
+
+		for _, kv := range s.Attributes() {
+			if kv.Key == "http.response.status_code" && kv.Value.AsInt64() >= 500 {
+				return true
+			}
+		}
+	}
+
+	return p.slowThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= p.slowThreshold
+}
+
+// keptSpan 将 span 标记为已采样，使下游 BatchSpanProcessor 与导出器接受该 span。
+type keptSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s *keptSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,215 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+func TestNewTraceSampler(t *testing.T) {
+	t.Run("不支持的策略", func(t *testing.T) {
+		_, err := NewTraceSampler("sometimes", "", true)
+
+		testingv2.Then(t, "返回错误",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("比例越界", func(t *testing.T) {
+		_, err := NewTraceSampler("ratio:1.5", "", true)
+
+		testingv2.Then(t, "返回错误",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("按路由采样并保留出错的 span", func(t *testing.T) {
+		sampler := testingv2.MustValue(t, func() (sdktrace.Sampler, error) {
+			return NewTraceSampler("ratio:0", "/keep/*=1", true)
+		})
+
+		recorder := tracetest.NewSpanRecorder()
+
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sampler),
+			sdktrace.WithSpanProcessor(KeepSpanProcessor(recorder, true, time.Hour)),
+		)
+		t.Cleanup(func() {
+			_ = tp.Shutdown(context.Background())
+		})
+
+		tracer := tp.Tracer("")
+		ctx := context.Background()
+
+		start := func(name string, route string) trace.Span {
+			_, span := tracer.Start(ctx, name, trace.WithAttributes(attribute.String("http.route", route)))
+			return span
+		}
+
+		matched := start("matched", "/keep/a")
+		matched.End()
+
+		healthy := start("healthy", "/other")
+		healthy.End()
+
+		failed := start("failed", "/other")
+		failed.SetStatus(codes.Error, "boom")
+		failed.End()
+
+		ended := recorder.Ended()
+
+		names := make([]string, 0, len(ended))
+		sampled := make([]bool, 0, len(ended))
+		for _, s := range ended {
+			names = append(names, s.Name())
+			sampled = append(sampled, s.SpanContext().IsSampled())
+		}
+
+		testingv2.Then(t, "命中规则的 span 被采样，未命中的正常 span 被丢弃，出错的 span 被保留并标记为已采样",
+			testingv2.Expect(names, testingv2.Equal([]string{"matched", "failed"})),
+			testingv2.Expect(sampled, testingv2.Equal([]bool{true, true})),
+		)
+	})
+
+	t.Run("未启用保留时不记录未采样的 span", func(t *testing.T) {
+		sampler := testingv2.MustValue(t, func() (sdktrace.Sampler, error) {
+			return NewTraceSampler("ratio:0", "", false)
+		})
+
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       trace.TraceID{1},
+			Name:          "dropped",
+		})
+
+		testingv2.Then(t, "未被采样的 span 直接丢弃",
+			testingv2.Expect(result.Decision, testingv2.Equal(sdktrace.Drop)),
+		)
+	})
+
+	t.Run("保留出错 span 的本地祖先", func(t *testing.T) {
+		sampler := testingv2.MustValue(t, func() (sdktrace.Sampler, error) {
+			return NewTraceSampler("ratio:0", "", true)
+		})
+
+		recorder := tracetest.NewSpanRecorder()
+
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sampler),
+			sdktrace.WithSpanProcessor(KeepSpanProcessor(recorder, true, 0)),
+		)
+		t.Cleanup(func() {
+			_ = tp.Shutdown(context.Background())
+		})
+
+		tracer := tp.Tracer("")
+
+		ctx, root := tracer.Start(context.Background(), "root")
+		ctx, parent := tracer.Start(ctx, "parent")
+
+		_, healthy := tracer.Start(ctx, "healthy")
+		healthy.End()
+
+		_, failed := tracer.Start(ctx, "failed")
+		failed.SetStatus(codes.Error, "boom")
+		failed.End()
+
+		parent.End()
+		root.End()
+
+		_, other := tracer.Start(context.Background(), "other")
+		other.End()
+
+		ended := recorder.Ended()
+
+		names := make([]string, 0, len(ended))
+		for _, s := range ended {
+			names = append(names, s.Name())
+		}
+
+		testingv2.Then(t, "出错 span 的父 span 直至本地根 span 一同保留，兄弟 span 与其他链路不受影响",
+			testingv2.Expect(names, testingv2.Equal([]string{"failed", "parent", "root"})),
+		)
+	})
+
+	t.Run("待保留祖先的记录", func(t *testing.T) {
+		sampler := testingv2.MustValue(t, func() (sdktrace.Sampler, error) {
+			return NewTraceSampler("ratio:0", "", true)
+		})
+
+		p := KeepSpanProcessor(tracetest.NewSpanRecorder(), true, 0).(*keepSpanProcessor)
+
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sampler),
+			sdktrace.WithSpanProcessor(p),
+		)
+		t.Cleanup(func() {
+			_ = tp.Shutdown(context.Background())
+		})
+
+		tracer := tp.Tracer("")
+
+		ctx, root := tracer.Start(context.Background(), "root")
+		ctx, parent := tracer.Start(ctx, "parent")
+		_, failed := tracer.Start(ctx, "failed")
+		failed.SetStatus(codes.Error, "boom")
+
+		// 子 span 晚于本地根 span 结束，对 root 的标记无法再被取出
+		root.End()
+		failed.End()
+		parent.End()
+
+		leaked := len(p.keptChildren)
+
+		for i := range maxKeptTraces {
+			p.keptChildren[trace.TraceID{byte(i >> 8), byte(i), 1}] = map[trace.SpanID]struct{}{}
+		}
+
+		ctx, root = tracer.Start(context.Background(), "root")
+		_, failed = tracer.Start(ctx, "failed")
+		failed.SetStatus(codes.Error, "boom")
+		failed.End()
+
+		marked := len(p.keptChildren)
+
+		root.End()
+
+		testingv2.Then(t, "本地根 span 结束时清除链路的记录，记录达到上限时整体丢弃",
+			testingv2.Expect(leaked, testingv2.Equal(1)),
+			testingv2.Expect(marked, testingv2.Equal(1)),
+			testingv2.Expect(len(p.keptChildren), testingv2.Equal(0)),
+		)
+	})
+
+	t.Run("关闭出错保留", func(t *testing.T) {
+		sampler := testingv2.MustValue(t, func() (sdktrace.Sampler, error) {
+			return NewTraceSampler("ratio:0", "", true)
+		})
+
+		recorder := tracetest.NewSpanRecorder()
+
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sampler),
+			sdktrace.WithSpanProcessor(KeepSpanProcessor(recorder, false, time.Hour)),
+		)
+		t.Cleanup(func() {
+			_ = tp.Shutdown(context.Background())
+		})
+
+		_, failed := tp.Tracer("").Start(context.Background(), "failed")
+		failed.SetStatus(codes.Error, "boom")
+		failed.End()
+
+		testingv2.Then(t, "出错的 span 按采样策略丢弃",
+			testingv2.Expect(len(recorder.Ended()), testingv2.Equal(0)),
+		)
+	})
+}
@@ -6,6 +6,42 @@ import (
 	fmt "fmt"
 )
 
+var InvalidLogDropPolicy = errors.New("invalid LogDropPolicy")
+
+func (LogDropPolicy) EnumValues() []any {
+	return []any{
+		LogDropPolicyBlock, LogDropPolicyDropDebugFirst, LogDropPolicyDropNewest,
+	}
+}
+
+func ParseLogDropPolicyLabelString(label string) (LogDropPolicy, error) {
+	switch label {
+	case "block":
+		return LogDropPolicyBlock, nil
+	case "drop-debug-first":
+		return LogDropPolicyDropDebugFirst, nil
+	case "drop-newest":
+		return LogDropPolicyDropNewest, nil
+
+	default:
+		return *new(LogDropPolicy), InvalidLogDropPolicy
+	}
+}
+
+func (v LogDropPolicy) Label() string {
+	switch v {
+	case LogDropPolicyBlock:
+		return "block"
+	case LogDropPolicyDropDebugFirst:
+		return "drop-debug-first"
+	case LogDropPolicyDropNewest:
+		return "drop-newest"
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 var InvalidLogFormat = errors.New("invalid LogFormat")
 
 func (LogFormat) EnumValues() []any {
@@ -0,0 +1,102 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/octohelm/x/logr"
+
+	"github.com/innoai-tech/infra/pkg/otel/internal/otel"
+)
+
+// LogLevelController 提供运行期间查看与修改日志级别的能力。
+// +gengo:injectable:provider
+type LogLevelController interface {
+	// LogLevels 返回当前全局日志级别及按名称的覆盖
+	LogLevels() LogLevels
+	// SetLogLevel 修改日志级别，name 为空时修改全局级别，否则覆盖该名称（logr.Start 的 span 名）的级别；
+	// ttl 大于 0 时到期后自动恢复，level 为空时移除覆盖
+	SetLogLevel(name string, level LogLevel, ttl time.Duration) error
+}
+
+// LogLevels 表示当前日志级别状态。
+type LogLevels struct {
+	// Level 全局日志级别
+	Level LogLevel `json:"level"`
+	// ExpiresAt 全局临时级别的过期时间
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Loggers 按日志记录器名称的级别覆盖
+	Loggers map[string]LoggerLevel `json:"loggers,omitempty"`
+}
+
+// LoggerLevel 表示单个日志记录器的级别覆盖。
+type LoggerLevel struct {
+	// Level 日志级别
+	Level LogLevel `json:"level"`
+	// ExpiresAt 过期时间，为空时不过期
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ContextWithLogLevel 返回覆盖了日志级别的上下文，该上下文中启动的日志记录器均使用该级别。
+func ContextWithLogLevel(ctx context.Context, level logr.Level) context.Context {
+	return otel.ContextWithLogLevel(ctx, level)
+}
+
+var _ LogLevelController = &Otel{}
+
+// LogLevels 返回当前全局日志级别及按名称的覆盖。
+func (o *Otel) LogLevels() LogLevels {
+	levels := LogLevels{
+		Level: otel.LogLevelOf(o.enabledLevel.Level()),
+	}
+
+	if t, ok := o.enabledLevel.Temporary(); ok && !t.ExpiresAt.IsZero() {
+		levels.ExpiresAt = &t.ExpiresAt
+	}
+
+	if overrides := o.enabledLevel.Overrides(); len(overrides) > 0 {
+		levels.Loggers = make(map[string]LoggerLevel, len(overrides))
+
+		for name, override := range overrides {
+			l := LoggerLevel{Level: otel.LogLevelOf(override.Level)}
+			if !override.ExpiresAt.IsZero() {
+				l.ExpiresAt = &override.ExpiresAt
+			}
+			levels.Loggers[name] = l
+		}
+	}
+
+	return levels
+}
+
+// SetLogLevel 修改全局或指定名称日志记录器的级别。
+func (o *Otel) SetLogLevel(name string, level LogLevel, ttl time.Duration) error {
+	if level == "" {
+		if name == "" {
+			o.enabledLevel.ClearTemporary()
+			return nil
+		}
+		o.enabledLevel.DeleteOverride(name)
+		return nil
+	}
+
+	l, err := logr.ParseLevel(string(level))
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		o.enabledLevel.SetOverride(name, l, ttl)
+		return nil
+	}
+
+	if ttl > 0 {
+		o.enabledLevel.SetTemporary(l, ttl)
+		return nil
+	}
+
+	o.enabledLevel.ClearTemporary()
+	o.enabledLevel.Set(l)
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package otel
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/innoai-tech/infra/pkg/otel/internal/otel"
+	"github.com/innoai-tech/infra/pkg/otel/metric"
+)
+
+const (
+	batchLogExportSize     = 256
+	batchLogExportInterval = 200 * time.Millisecond
+)
+
+var logDropped = metric.NewInt64Counter(
+	"otel.log.dropped",
+	metric.WithUnit("{record}"),
+	metric.WithDescription("Number of log records dropped by the async log processor"),
+)
+
+// batchLogProcessor 将日志记录放入有界队列，由后台协程批量交给导出器，避免慢终端或管道阻塞请求。
+type batchLogProcessor struct {
+	exporter  sdklog.Exporter
+	policy    LogDropPolicy
+	queueSize int
+
+	// metricCtx 携带 MeterProvider，用于记录丢弃数
+	metricCtx context.Context
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	queue   []sdklog.Record
+	closed  bool
+
+	// exporting 为导出互斥的信号量，获取时可随 ctx 放弃
+	exporting chan struct{}
+	// runCtx 为后台协程导出时使用的上下文，Shutdown 超时时取消，以免阻塞的导出占住 exporting
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+
+	flush  chan struct{}
+	done   chan struct{}
+	exited chan struct{}
+}
+
+func newBatchLogProcessor(metricCtx context.Context, exporter sdklog.Exporter, queueSize int, policy LogDropPolicy) *batchLogProcessor {
+	p := &batchLogProcessor{
+		exporter:  exporter,
+		policy:    policy,
+		queueSize: queueSize,
+		metricCtx: metricCtx,
+		queue:     make([]sdklog.Record, 0, queueSize),
+		exporting: make(chan struct{}, 1),
+		flush:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		exited:    make(chan struct{}),
+	}
+
+	p.notFull = sync.NewCond(&p.mu)
+	p.runCtx, p.cancelRun = context.WithCancel(context.Background())
+
+	go p.run()
+
+	return p
+}
+
+var _ sdklog.Processor = &batchLogProcessor{}
+
+// Enabled 返回当前处理器是否启用。
+func (p *batchLogProcessor) Enabled(ctx context.Context, param sdklog.EnabledParameters) bool {
+	return true
+}
+
+// OnEmit 将日志记录放入队列；队列已满时按 LogDropPolicy 处理。
+func (p *batchLogProcessor) OnEmit(ctx context.Context, record *sdklog.Record) error {
+	r := record.Clone()
+
+	p.mu.Lock()
+
+	for len(p.queue) >= p.queueSize && !p.closed {
+		if p.policy == LogDropPolicyBlock {
+			p.notFull.Wait()
+			continue
+		}
+
+		if p.policy == LogDropPolicyDropDebugFirst && !isDebugRecord(r) {
+			if i := slices.IndexFunc(p.queue, isDebugRecord); i >= 0 {
+				evicted := p.queue[i]
+				p.queue = slices.Delete(p.queue, i, i+1)
+				p.queue = append(p.queue, r)
+				p.mu.Unlock()
+
+				p.dropped(evicted)
+				return nil
+			}
+		}
+
+		p.mu.Unlock()
+
+		p.dropped(r)
+		return nil
+	}
+
+	if p.closed {
+		p.mu.Unlock()
+
+		p.dropped(r)
+		return nil
+	}
+
+	p.queue = append(p.queue, r)
+	full := len(p.queue) >= batchLogExportSize
+
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// ForceFlush 导出队列中的全部日志记录。
+func (p *batchLogProcessor) ForceFlush(ctx context.Context) error {
+	if err := p.export(ctx); err != nil {
+		return err
+	}
+	return p.exporter.ForceFlush(ctx)
+}
+
+// Shutdown 停止后台协程并在 ctx 结束前导出队列中剩余的日志记录，未及导出的记为丢弃。
+//
+// ctx 结束时后台协程仍在进行的导出会被取消，Shutdown 不再等待其返回。
+func (p *batchLogProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	// 唤醒阻塞中的写入
+	p.notFull.Broadcast()
+	p.mu.Unlock()
+
+	close(p.done)
+	defer p.cancelRun()
+
+	select {
+	case <-p.exited:
+	case <-ctx.Done():
+		p.cancelRun()
+	}
+
+	err := p.export(ctx)
+
+	p.mu.Lock()
+	remains := p.queue
+	p.queue = nil
+	p.mu.Unlock()
+
+	for _, r := range remains {
+		p.dropped(r)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return p.exporter.Shutdown(ctx)
+}
+
+func (p *batchLogProcessor) run() {
+	defer close(p.exited)
+
+	ticker := time.NewTicker(batchLogExportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		case <-p.flush:
+		}
+
+		_ = p.export(p.runCtx)
+	}
+}
+
+// export 分批导出队列中的日志记录，直至队列为空或 ctx 结束；等待其他导出完成时同样随 ctx 结束返回。
+func (p *batchLogProcessor) export(ctx context.Context) error {
+	select {
+	case p.exporting <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() {
+		<-p.exporting
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		n := min(len(p.queue), batchLogExportSize)
+		if n == 0 {
+			p.mu.Unlock()
+			return nil
+		}
+		batch := slices.Clone(p.queue[:n])
+		p.queue = slices.Delete(p.queue, 0, n)
+		p.notFull.Broadcast()
+		p.mu.Unlock()
+
+		if err := p.exporter.Export(ctx, batch); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *batchLogProcessor) dropped(r sdklog.Record) {
+	logDropped.Add(p.metricCtx, 1, otelmetric.WithAttributes(
+		attribute.String("log.severity", r.Severity().String()),
+		attribute.String("policy", string(p.policy)),
+	))
+}
+
+func isDebugRecord(r sdklog.Record) bool {
+	return r.Severity() != log.SeverityUndefined && r.Severity() < log.SeverityInfo1
+}
+
+// metricContext 返回携带 MeterProvider 的上下文。
+func metricContext(mp otel.MeterProvider) context.Context {
+	return otel.MeterProviderContext.Inject(context.Background(), mp)
+}
@@ -0,0 +1,147 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	testingv2 "github.com/octohelm/x/testing/v2"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.records = append(e.records, records...)
+	return nil
+}
+
+func (e *recordingExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) bodies() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bodies := make([]string, 0, len(e.records))
+	for _, r := range e.records {
+		bodies = append(bodies, r.Body().AsString())
+	}
+	return bodies
+}
+
+// blockingExporter 的 Export 忽略 ctx，直至 release 关闭才返回。
+type blockingExporter struct {
+	recordingExporter
+
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (e *blockingExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.once.Do(func() {
+		close(e.started)
+	})
+	<-e.release
+	return e.recordingExporter.Export(ctx, records)
+}
+
+func newRecord(severity log.Severity, body string) *sdklog.Record {
+	r := &sdklog.Record{}
+	r.SetSeverity(severity)
+	r.SetBody(attribute.StringValue(body))
+	return r
+}
+
+func TestBatchLogProcessor(t *testing.T) {
+	t.Run("队列满时优先丢弃 debug 日志", func(t *testing.T) {
+		p := &batchLogProcessor{
+			policy:    LogDropPolicyDropDebugFirst,
+			queueSize: 2,
+			metricCtx: context.Background(),
+		}
+		p.notFull = sync.NewCond(&p.mu)
+
+		ctx := context.Background()
+
+		_ = p.OnEmit(ctx, newRecord(log.SeverityDebug, "debug"))
+		_ = p.OnEmit(ctx, newRecord(log.SeverityInfo, "info 1"))
+		_ = p.OnEmit(ctx, newRecord(log.SeverityInfo, "info 2"))
+		_ = p.OnEmit(ctx, newRecord(log.SeverityInfo, "info 3"))
+
+		bodies := make([]string, 0)
+		for _, r := range p.queue {
+			bodies = append(bodies, r.Body().AsString())
+		}
+
+		testingv2.Then(t, "debug 日志被新日志替换，无 debug 可丢时丢弃新日志",
+			testingv2.Expect(bodies, testingv2.Equal([]string{"info 1", "info 2"})),
+		)
+	})
+
+	t.Run("Shutdown 时导出队列中剩余日志", func(t *testing.T) {
+		exporter := &recordingExporter{}
+
+		p := newBatchLogProcessor(context.Background(), exporter, 16, LogDropPolicyBlock)
+
+		ctx := context.Background()
+
+		_ = p.OnEmit(ctx, newRecord(log.SeverityInfo, "a"))
+		_ = p.OnEmit(ctx, newRecord(log.SeverityWarn, "b"))
+
+		testingv2.Must(t, func() error {
+			return p.Shutdown(ctx)
+		})
+
+		testingv2.Then(t, "全部日志按序导出",
+			testingv2.Expect(exporter.bodies(), testingv2.Equal([]string{"a", "b"})),
+		)
+	})
+
+	t.Run("导出阻塞时 Shutdown 随 ctx 返回", func(t *testing.T) {
+		exporter := &blockingExporter{
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		t.Cleanup(func() {
+			close(exporter.release)
+		})
+
+		p := newBatchLogProcessor(context.Background(), exporter, 16, LogDropPolicyBlock)
+
+		_ = p.OnEmit(context.Background(), newRecord(log.SeverityInfo, "a"))
+
+		// 等待后台协程开始导出
+		<-exporter.started
+
+		_ = p.OnEmit(context.Background(), newRecord(log.SeverityInfo, "b"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		err := p.Shutdown(ctx)
+
+		testingv2.Then(t, "不等待阻塞的导出，返回 ctx 错误",
+			testingv2.Expect(errors.Is(err, context.DeadlineExceeded), testingv2.Equal(true)),
+			testingv2.Expect(time.Since(started) < time.Second, testingv2.Equal(true)),
+		)
+	})
+}
@@ -40,7 +40,7 @@ func NewInt64Histogram(name string, optFuncs ...OptionFunc) Int64Recorder {
 
 	return &int64Instrument{
 		option: o,
-		histogram: func(meter otelmetric.Meter) (Int64Recorder, error) {
+		recorder: func(meter otelmetric.Meter) (Int64Recorder, error) {
 			return meter.Int64Histogram(o.Name, otelmetric.WithUnit(o.Unit), otelmetric.WithDescription(o.Description))
 		},
 	}
@@ -73,16 +73,27 @@ func NewFloat64Histogram(name string, optFuncs ...OptionFunc) Float64Recorder {
 
 	return &float64Instrument{
 		option: o,
-		histogram: func(meter otelmetric.Meter) (Float64Recorder, error) {
+		recorder: func(meter otelmetric.Meter) (Float64Recorder, error) {
 			return meter.Float64Histogram(o.Name, otelmetric.WithUnit(o.Unit), otelmetric.WithDescription(o.Description))
 		},
 	}
 }
 
+func NewFloat64Gauge(name string, optFuncs ...OptionFunc) Float64Recorder {
+	o := newOption(name, optFuncs...)
+
+	return &float64Instrument{
+		option: o,
+		recorder: func(meter otelmetric.Meter) (Float64Recorder, error) {
+			return meter.Float64Gauge(o.Name, otelmetric.WithUnit(o.Unit), otelmetric.WithDescription(o.Description))
+		},
+	}
+}
+
 type int64Instrument struct {
 	*option
-	counter   func(meter otelmetric.Meter) (Int64Counter, error)
-	histogram func(meter otelmetric.Meter) (Int64Recorder, error)
+	counter  func(meter otelmetric.Meter) (Int64Counter, error)
+	recorder func(meter otelmetric.Meter) (Int64Recorder, error)
 }
 
 func (i *int64Instrument) Add(ctx context.Context, incr int64, options ...otelmetric.AddOption) {
@@ -92,15 +103,15 @@ func (i *int64Instrument) Add(ctx context.Context, incr int64, options ...otelme
 }
 
 func (i *int64Instrument) Record(ctx context.Context, incr int64, options ...otelmetric.RecordOption) {
-	if c, err := i.histogram(otel.Meter(ctx)); err == nil {
+	if c, err := i.recorder(otel.Meter(ctx)); err == nil {
 		c.Record(ctx, incr, options...)
 	}
 }
 
 type float64Instrument struct {
 	*option
-	counter   func(meter otelmetric.Meter) (Float64Counter, error)
-	histogram func(meter otelmetric.Meter) (Float64Recorder, error)
+	counter  func(meter otelmetric.Meter) (Float64Counter, error)
+	recorder func(meter otelmetric.Meter) (Float64Recorder, error)
 }
 
 func (i *float64Instrument) Add(ctx context.Context, incr float64, options ...otelmetric.AddOption) {
@@ -110,7 +121,7 @@ func (i *float64Instrument) Add(ctx context.Context, incr float64, options ...ot
 }
 
 func (i *float64Instrument) Record(ctx context.Context, incr float64, options ...otelmetric.RecordOption) {
-	if c, err := i.histogram(otel.Meter(ctx)); err == nil {
+	if c, err := i.recorder(otel.Meter(ctx)); err == nil {
 		c.Record(ctx, incr, options...)
 	}
 }
@@ -6,9 +6,6 @@ import (
 
 	"go.opentelemetry.io/contrib/instrumentation/host"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -48,35 +45,102 @@ const (
 	LogFormatJSON = otel.LogFormatJSON
 )
 
+// TextMapPropagator 是对 OpenTelemetry propagation.TextMapPropagator 的公开别名。
+type TextMapPropagator = otel.TextMapPropagator
+
+// PropagatorFromContext 返回上下文中由 Otel.Propagators 配置的传播器，未注入时使用 W3C TraceContext、Baggage 与 B3 的组合。
+func PropagatorFromContext(ctx context.Context) TextMapPropagator {
+	return otel.PropagatorContext.From(ctx)
+}
+
+// LogDropPolicy 表示异步日志队列满时的处理策略。
+type LogDropPolicy = otel.LogDropPolicy
+
+const (
+	// LogDropPolicyDropDebugFirst 表示优先丢弃 debug 日志，无 debug 日志可丢时丢弃新日志。
+	LogDropPolicyDropDebugFirst = otel.LogDropPolicyDropDebugFirst
+	// LogDropPolicyDropNewest 表示丢弃新日志。
+	LogDropPolicyDropNewest = otel.LogDropPolicyDropNewest
+	// LogDropPolicyBlock 表示阻塞写入直至队列有空位。
+	LogDropPolicyBlock = otel.LogDropPolicyBlock
+)
+
 // Otel 提供日志、trace 和 metric 的统一装配入口。
 // +gengo:injectable
 type Otel struct {
 	// LogLevel 日志级别
-	LogLevel LogLevel `flag:",omitzero"`
+	LogLevel LogLevel `flag:",omitzero,reload"`
 	// LogFormat 日志格式
 	LogFormat LogFormat `flag:",omitzero"`
-	// TraceCollectorEndpoint 设置后将启用 trace 采集
+	// LogAsync 启用后日志经有界队列批量异步输出，避免慢终端或管道阻塞请求
+	LogAsync bool `flag:",omitzero"`
+	// LogQueueSize 异步日志队列长度
+	LogQueueSize int `flag:",omitzero" validate:"min=0"`
+	// LogDropPolicy 异步日志队列满时的处理策略
+	LogDropPolicy LogDropPolicy `flag:",omitzero"`
+	// LogSampleFirst 每个周期内同一 (级别, 消息模板, source.func) 保留的前 N 条日志，为 0 时关闭采样
+	LogSampleFirst int `flag:",omitzero" validate:"min=0"`
+	// LogSampleThereafter 超过前 N 条后每 M 条保留 1 条，小于 0 时全部抑制
+	LogSampleThereafter int `flag:",omitzero"`
+	// LogSampleIntervalSeconds 采样统计周期（秒），每个周期结束时输出被抑制日志的汇总
+	LogSampleIntervalSeconds int `flag:",omitzero" validate:"min=0"`
+	// LogSampleErrors 允许对 error 日志采样，默认 error 日志总是输出
+	LogSampleErrors bool `flag:",omitzero"`
+	// TraceCollectorEndpoint 设置后将启用 trace 采集，scheme 为 grpc、grpcs、http、https，不带 scheme 时为明文 gRPC
 	TraceCollectorEndpoint string `flag:",omitzero"`
-
-	// MetricCollectorEndpoint 指标采集器地址
+	// TraceSampler trace 采样策略，可选 always、never、ratio:<f>、parentbased_ratio:<f>
+	TraceSampler string `flag:",omitzero"`
+	// TraceSampleRules 按 http.route 的采样比例，如 /healthz=0,/api/*=0.1，优先于 TraceSampler
+	TraceSampleRules string `flag:",omitzero"`
+	// TraceSlowThresholdMilliseconds 耗时不小于该值（毫秒）的 span 总是上报，小于 0 时关闭
+	TraceSlowThresholdMilliseconds int `flag:",omitzero"`
+	// TraceDropErrors 出错的 span 按采样策略处理，默认总是上报
+	TraceDropErrors bool `flag:",omitzero"`
+	// Propagators 以逗号分隔的 trace 传播格式，可选 tracecontext、baggage、b3、b3single、none
+	Propagators string `flag:",omitzero"`
+
+	// MetricCollectorEndpoint 指标采集器地址，格式同 TraceCollectorEndpoint
 	MetricCollectorEndpoint string `flag:",omitzero"`
 	// MetricCollectIntervalSeconds 指标采集间隔（秒）
 	MetricCollectIntervalSeconds int `flag:",omitzero"`
 
+	// LogCollectorEndpoint 设置后将日志同时上报至该采集器，格式同 TraceCollectorEndpoint
+	LogCollectorEndpoint string `flag:",omitzero"`
+
+	// CollectorTLSCAFile 校验采集器证书的 CA 文件，用于 grpcs 与 https
+	CollectorTLSCAFile string `flag:",omitzero"`
+	// CollectorTLSCertFile 上报采集器的客户端证书文件
+	CollectorTLSCertFile string `flag:",omitzero"`
+	// CollectorTLSKeyFile 上报采集器的客户端私钥文件
+	CollectorTLSKeyFile string `flag:",omitzero"`
+	// CollectorHeaders 上报采集器的请求头，如 authorization=Bearer%20xxx，多个以逗号分隔
+	CollectorHeaders string `flag:",omitzero,secret"`
+	// CollectorCompression 上报压缩方式，可选 none、gzip
+	CollectorCompression string `flag:",omitzero"`
+	// CollectorTimeoutSeconds 上报超时（秒）
+	CollectorTimeoutSeconds int `flag:",omitzero" validate:"min=0"`
+
 	tracerProvider *sdktrace.TracerProvider
 	loggerProvider *sdklog.LoggerProvider
 	meterProvider  *sdkmetric.MeterProvider
 
 	metricReader sdkmetric.Reader
 
-	enabledLevel logr.Level
+	propagator otel.TextMapPropagator
+
+	enabledLevel otel.LevelVar
+	// configuredLevel 最近一次由配置应用的日志级别，配置未变化时重载不覆盖运行期间设置的级别
+	configuredLevel LogLevel
 
 	dynamicLogProcessor *dynamicLogProcessor
 
+	logSampler     *otel.LogSampler
+	stopLogSampler func()
+
 	info *appinfo.Info `inject:",opt"`
 }
 
-// SetDefaults 补齐默认日志级别、格式和指标上报周期。
+// SetDefaults 补齐默认日志级别、格式、异步日志队列、日志采样、trace 采样与传播方式、采集器上报选项和指标上报周期。
 func (o *Otel) SetDefaults() {
 	if o.LogLevel == "" {
 		o.LogLevel = InfoLevel
@@ -86,6 +150,44 @@ func (o *Otel) SetDefaults() {
 		o.LogFormat = LogFormatJSON
 	}
 
+	if o.LogQueueSize == 0 {
+		o.LogQueueSize = 2048
+	}
+
+	if o.LogDropPolicy == "" {
+		o.LogDropPolicy = LogDropPolicyDropDebugFirst
+	}
+
+	if o.LogSampleFirst > 0 {
+		if o.LogSampleThereafter == 0 {
+			o.LogSampleThereafter = 100
+		}
+
+		if o.LogSampleIntervalSeconds == 0 {
+			o.LogSampleIntervalSeconds = 1
+		}
+	}
+
+	if o.TraceSampler == "" {
+		o.TraceSampler = "always"
+	}
+
+	if o.TraceSlowThresholdMilliseconds == 0 {
+		o.TraceSlowThresholdMilliseconds = 1000
+	}
+
+	if o.Propagators == "" {
+		o.Propagators = otel.DefaultPropagators
+	}
+
+	if o.CollectorCompression == "" {
+		o.CollectorCompression = "none"
+	}
+
+	if o.CollectorTimeoutSeconds == 0 {
+		o.CollectorTimeoutSeconds = 3
+	}
+
 	if o.MetricCollectorEndpoint != "" {
 		if o.MetricCollectIntervalSeconds == 0 {
 			o.MetricCollectIntervalSeconds = 60
@@ -99,13 +201,15 @@ func (o *Otel) InjectContext(ctx context.Context) context.Context {
 		ctx,
 		configuration.InjectContextFunc(otel.TracerProviderContext.Inject, otel.TracerProvider(o.tracerProvider)),
 		configuration.InjectContextFunc(otel.LoggerProviderContext.Inject, otel.LoggerProvider(o.loggerProvider)),
+		configuration.InjectContextFunc(otel.PropagatorContext.Inject, o.propagator),
 	)
 
-	l := otel.NewLogger(ctx, o.enabledLevel)
+	l := otel.NewLogger(ctx, &o.enabledLevel, o.logSampler)
 
 	return configuration.InjectContext(
 		ctx,
 		configuration.InjectContextFunc(LogProcessorRegistryInjectContext, LogProcessorRegistry(o.dynamicLogProcessor)),
+		configuration.InjectContextFunc(LogLevelControllerInjectContext, LogLevelController(o)),
 		configuration.InjectContextFunc(logr.WithLogger, l),
 		configuration.InjectContextFunc(otel.MeterProviderContext.Inject, otel.MeterProvider(o.meterProvider)),
 		configuration.InjectContextFunc(otel.MetricReaderContext.Inject, o.metricReader),
@@ -123,18 +227,30 @@ func (o *Otel) afterInit(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	o.enabledLevel = enabledLevel
+	o.enabledLevel.Set(enabledLevel)
+	o.configuredLevel = o.LogLevel
+
+	propagator, err := otel.ParsePropagators(o.Propagators)
+	if err != nil {
+		return err
+	}
+	o.propagator = propagator
+
+	// 仅在未被采样的 span 可能被保留时记录这些 span
+	keepSpans := o.TraceCollectorEndpoint != "" && (!o.TraceDropErrors || o.TraceSlowThresholdMilliseconds > 0)
+
+	sampler, err := otel.NewTraceSampler(o.TraceSampler, o.TraceSampleRules, keepSpans)
+	if err != nil {
+		return err
+	}
 
 	o.metricReader = sdkmetric.NewManualReader()
 
 	tracerOpts := []sdktrace.TracerProviderOption{
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(sampler),
 	}
 
 	logOpts := []sdklog.LoggerProviderOption{
-		sdklog.WithProcessor(
-			sdklog.NewSimpleProcessor(otel.SlogExporter(o.LogFormat)),
-		),
 		sdklog.WithProcessor(o.dynamicLogProcessor),
 	}
 
@@ -153,33 +269,42 @@ func (o *Otel) afterInit(ctx context.Context) error {
 		meterOpts = append(meterOpts, sdkmetric.WithResource(res))
 	}
 
+	collectorOpts, err := o.collectorOptions()
+	if err != nil {
+		return err
+	}
+
 	if o.TraceCollectorEndpoint != "" {
-		client := otlptracegrpc.NewClient(
-			otlptracegrpc.WithEndpoint(o.TraceCollectorEndpoint),
-			otlptracegrpc.WithInsecure(),
-			otlptracegrpc.WithTimeout(3*time.Second),
-		)
+		endpoint, err := parseCollectorEndpoint(o.TraceCollectorEndpoint)
+		if err != nil {
+			return err
+		}
 
-		exporter, err := otlptrace.New(ctx, client)
+		exporter, err := newTraceExporter(ctx, endpoint, collectorOpts)
 		if err != nil {
 			return err
 		}
 
-		tracerOpts = append(
-			tracerOpts,
-			sdktrace.WithBatcher(
-				otel.IgnoreErrSpanExporter(exporter),
-			),
-		)
+		var processor sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(otel.IgnoreErrSpanExporter(otel.RedactSpanExporter(exporter, otel.DefaultRedactor)))
+
+		if keepSpans {
+			processor = otel.KeepSpanProcessor(
+				processor,
+				!o.TraceDropErrors,
+				time.Duration(o.TraceSlowThresholdMilliseconds)*time.Millisecond,
+			)
+		}
+
+		tracerOpts = append(tracerOpts, sdktrace.WithSpanProcessor(processor))
 	}
 
 	if o.MetricCollectorEndpoint != "" {
-		exporter, err := otlpmetricgrpc.New(
-			ctx,
-			otlpmetricgrpc.WithEndpoint(o.MetricCollectorEndpoint),
-			otlpmetricgrpc.WithInsecure(),
-			otlpmetricgrpc.WithTimeout(3*time.Second),
-		)
+		endpoint, err := parseCollectorEndpoint(o.MetricCollectorEndpoint)
+		if err != nil {
+			return err
+		}
+
+		exporter, err := newMetricExporter(ctx, endpoint, collectorOpts)
 		if err != nil {
 			return err
 		}
@@ -195,14 +320,41 @@ func (o *Otel) afterInit(ctx context.Context) error {
 		)
 	}
 
+	if o.LogCollectorEndpoint != "" {
+		endpoint, err := parseCollectorEndpoint(o.LogCollectorEndpoint)
+		if err != nil {
+			return err
+		}
+
+		exporter, err := newLogExporter(ctx, endpoint, collectorOpts)
+		if err != nil {
+			return err
+		}
+
+		logOpts = append(logOpts, sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	}
+
 	meterOpts = append(
 		meterOpts,
 		metric.GetMetricViewsOption(),
 	)
 
-	o.loggerProvider = sdklog.NewLoggerProvider(logOpts...)
-	o.tracerProvider = sdktrace.NewTracerProvider(tracerOpts...)
 	o.meterProvider = sdkmetric.NewMeterProvider(meterOpts...)
+	o.tracerProvider = sdktrace.NewTracerProvider(tracerOpts...)
+
+	// 终端输出位于首位，异步模式的丢弃计数依赖 meterProvider
+	var slogProcessor sdklog.Processor = sdklog.NewSimpleProcessor(otel.SlogExporter(o.LogFormat))
+	if o.LogAsync {
+		slogProcessor = newBatchLogProcessor(metricContext(o.meterProvider), otel.SlogExporter(o.LogFormat), o.LogQueueSize, o.LogDropPolicy)
+	}
+
+	o.loggerProvider = sdklog.NewLoggerProvider(
+		append([]sdklog.LoggerProviderOption{sdklog.WithProcessor(slogProcessor)}, logOpts...)...,
+	)
+
+	if o.LogSampleFirst > 0 {
+		o.startLogSampler()
+	}
 
 	if err := host.Start(host.WithMeterProvider(o.meterProvider)); err != nil {
 		return err
@@ -214,8 +366,64 @@ func (o *Otel) afterInit(ctx context.Context) error {
 	return nil
 }
 
+// startLogSampler 启用日志采样，并在每个统计周期结束时输出被抑制日志的汇总。
+func (o *Otel) startLogSampler() {
+	interval := time.Duration(o.LogSampleIntervalSeconds) * time.Second
+
+	o.logSampler = otel.NewLogSampler(o.LogSampleFirst, o.LogSampleThereafter, o.LogSampleErrors)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				o.logSampler.Flush(ctx, o.loggerProvider, &o.enabledLevel, interval)
+			}
+		}
+	}()
+
+	o.stopLogSampler = func() {
+		cancel()
+		<-done
+	}
+}
+
+// Reload 应用运行期间更新的日志级别。
+//
+// 仅在配置的日志级别变化时生效，配置未变化时保留经 /.sys/loglevel 设置的级别。
+func (o *Otel) Reload(ctx context.Context) error {
+	if o.LogLevel == o.configuredLevel {
+		return nil
+	}
+
+	enabledLevel, err := logr.ParseLevel(string(o.LogLevel))
+	if err != nil {
+		return err
+	}
+
+	o.enabledLevel.Set(enabledLevel)
+	o.configuredLevel = o.LogLevel
+
+	return nil
+}
+
 // Shutdown 刷新并关闭 trace、log、metric provider。
 func (o *Otel) Shutdown(c context.Context) error {
+	if o.stopLogSampler != nil {
+		o.stopLogSampler()
+		// 输出最后一个周期的汇总
+		o.logSampler.Flush(c, o.loggerProvider, &o.enabledLevel, time.Duration(o.LogSampleIntervalSeconds)*time.Second)
+	}
+
 	eg, ctx := errgroup.WithContext(c)
 
 	if tp := o.tracerProvider; tp != nil {
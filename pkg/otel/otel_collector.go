@@ -0,0 +1,263 @@
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// collectorEndpoint 为解析后的采集器地址。
+//
+// 不带 scheme 的 host:port 与 grpc:// 使用明文 gRPC，grpcs:// 使用 TLS gRPC，
+// http:// 与 https:// 使用 http/protobuf，路径为空时使用各信号的默认路径。
+type collectorEndpoint struct {
+	grpc     bool
+	insecure bool
+	host     string
+	path     string
+}
+
+func parseCollectorEndpoint(endpoint string) (*collectorEndpoint, error) {
+	if !strings.Contains(endpoint, "://") {
+		return &collectorEndpoint{grpc: true, insecure: true, host: endpoint}, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector endpoint %q: %w", endpoint, err)
+	}
+
+	e := &collectorEndpoint{host: u.Host}
+
+	switch u.Scheme {
+	case "grpc":
+		e.grpc, e.insecure = true, true
+	case "grpcs":
+		e.grpc = true
+	case "http":
+		e.insecure = true
+		e.path = u.Path
+	case "https":
+		e.path = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported collector endpoint scheme %q, should be one of grpc, grpcs, http, https", u.Scheme)
+	}
+
+	return e, nil
+}
+
+// collectorOptions 为各采集器共用的连接选项。
+type collectorOptions struct {
+	tlsConfig *tls.Config
+	headers   map[string]string
+	gzip      bool
+	timeout   time.Duration
+}
+
+func (o *Otel) collectorOptions() (*collectorOptions, error) {
+	opts := &collectorOptions{
+		gzip:    o.CollectorCompression == "gzip",
+		timeout: time.Duration(o.CollectorTimeoutSeconds) * time.Second,
+	}
+
+	switch o.CollectorCompression {
+	case "", "none", "gzip":
+	default:
+		return nil, fmt.Errorf("unsupported collector compression %q, should be one of none, gzip", o.CollectorCompression)
+	}
+
+	headers, err := parseCollectorHeaders(o.CollectorHeaders)
+	if err != nil {
+		return nil, err
+	}
+	opts.headers = headers
+
+	tlsConfig, err := o.collectorTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts.tlsConfig = tlsConfig
+
+	return opts, nil
+}
+
+// parseCollectorHeaders 解析 key=value,key2=value2 形式的请求头，值可为 URL 编码。
+func parseCollectorHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+
+	for kv := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid collector header, should be <key>=<value>")
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid collector header %s: %w", strings.TrimSpace(k), err)
+		}
+
+		headers[strings.TrimSpace(k)] = value
+	}
+
+	return headers, nil
+}
+
+func (o *Otel) collectorTLSConfig() (*tls.Config, error) {
+	conf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if o.CollectorTLSCAFile != "" {
+		pem, err := os.ReadFile(o.CollectorTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read collector tls ca file failed: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", o.CollectorTLSCAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if o.CollectorTLSCertFile != "" || o.CollectorTLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CollectorTLSCertFile, o.CollectorTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load collector tls client certificate failed: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+func newTraceExporter(ctx context.Context, endpoint *collectorEndpoint, opts *collectorOptions) (sdktrace.SpanExporter, error) {
+	if endpoint.grpc {
+		o := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(endpoint.host),
+			otlptracegrpc.WithHeaders(opts.headers),
+			otlptracegrpc.WithTimeout(opts.timeout),
+		}
+		if endpoint.insecure {
+			o = append(o, otlptracegrpc.WithInsecure())
+		} else {
+			o = append(o, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(opts.tlsConfig)))
+		}
+		if opts.gzip {
+			o = append(o, otlptracegrpc.WithCompressor("gzip"))
+		}
+		return otlptracegrpc.New(ctx, o...)
+	}
+
+	o := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.host),
+		otlptracehttp.WithHeaders(opts.headers),
+		otlptracehttp.WithTimeout(opts.timeout),
+	}
+	if endpoint.path != "" {
+		o = append(o, otlptracehttp.WithURLPath(endpoint.path))
+	}
+	if endpoint.insecure {
+		o = append(o, otlptracehttp.WithInsecure())
+	} else {
+		o = append(o, otlptracehttp.WithTLSClientConfig(opts.tlsConfig))
+	}
+	if opts.gzip {
+		o = append(o, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	return otlptracehttp.New(ctx, o...)
+}
+
+func newMetricExporter(ctx context.Context, endpoint *collectorEndpoint, opts *collectorOptions) (sdkmetric.Exporter, error) {
+	if endpoint.grpc {
+		o := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(endpoint.host),
+			otlpmetricgrpc.WithHeaders(opts.headers),
+			otlpmetricgrpc.WithTimeout(opts.timeout),
+		}
+		if endpoint.insecure {
+			o = append(o, otlpmetricgrpc.WithInsecure())
+		} else {
+			o = append(o, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(opts.tlsConfig)))
+		}
+		if opts.gzip {
+			o = append(o, otlpmetricgrpc.WithCompressor("gzip"))
+		}
+		return otlpmetricgrpc.New(ctx, o...)
+	}
+
+	o := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint.host),
+		otlpmetrichttp.WithHeaders(opts.headers),
+		otlpmetrichttp.WithTimeout(opts.timeout),
+	}
+	if endpoint.path != "" {
+		o = append(o, otlpmetrichttp.WithURLPath(endpoint.path))
+	}
+	if endpoint.insecure {
+		o = append(o, otlpmetrichttp.WithInsecure())
+	} else {
+		o = append(o, otlpmetrichttp.WithTLSClientConfig(opts.tlsConfig))
+	}
+	if opts.gzip {
+		o = append(o, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+	return otlpmetrichttp.New(ctx, o...)
+}
+
+func newLogExporter(ctx context.Context, endpoint *collectorEndpoint, opts *collectorOptions) (sdklog.Exporter, error) {
+	if endpoint.grpc {
+		o := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(endpoint.host),
+			otlploggrpc.WithHeaders(opts.headers),
+			otlploggrpc.WithTimeout(opts.timeout),
+		}
+		if endpoint.insecure {
+			o = append(o, otlploggrpc.WithInsecure())
+		} else {
+			o = append(o, otlploggrpc.WithTLSCredentials(credentials.NewTLS(opts.tlsConfig)))
+		}
+		if opts.gzip {
+			o = append(o, otlploggrpc.WithCompressor("gzip"))
+		}
+		return otlploggrpc.New(ctx, o...)
+	}
+
+	o := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(endpoint.host),
+		otlploghttp.WithHeaders(opts.headers),
+		otlploghttp.WithTimeout(opts.timeout),
+	}
+	if endpoint.path != "" {
+		o = append(o, otlploghttp.WithURLPath(endpoint.path))
+	}
+	if endpoint.insecure {
+		o = append(o, otlploghttp.WithInsecure())
+	} else {
+		o = append(o, otlploghttp.WithTLSClientConfig(opts.tlsConfig))
+	}
+	if opts.gzip {
+		o = append(o, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+	return otlploghttp.New(ctx, o...)
+}
@@ -3,6 +3,7 @@ package otel
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -63,3 +64,134 @@ func setup(t testing.TB, c any) context.Context {
 
 	return configuration.InjectContext(ctx, c.(configuration.ContextInjector))
 }
+
+func TestPropagators(t *testing.T) {
+	t.Run("默认组合 W3C 与 B3", func(t *testing.T) {
+		ctx := setup(t, &Otel{})
+
+		fields := PropagatorFromContext(ctx).Fields()
+
+		testingv2.Then(t, "同时包含 W3C traceparent 与 baggage 传播头",
+			testingv2.Expect(slices.Contains(fields, "traceparent"), testingv2.Equal(true)),
+			testingv2.Expect(slices.Contains(fields, "baggage"), testingv2.Equal(true)),
+		)
+	})
+
+	t.Run("仅启用 tracecontext", func(t *testing.T) {
+		ctx := setup(t, &Otel{Propagators: "tracecontext"})
+
+		testingv2.Then(t, "仅注入 W3C traceparent",
+			testingv2.Expect(
+				PropagatorFromContext(ctx).Fields(),
+				testingv2.Equal([]string{"traceparent", "tracestate"}),
+			),
+		)
+	})
+
+	t.Run("不支持的格式", func(t *testing.T) {
+		err := configuration.Init(t.Context(), &Otel{Propagators: "tracecontext,unknown"})
+
+		testingv2.Then(t, "初始化失败",
+			testingv2.Expect(err != nil, testingv2.Equal(true)),
+		)
+	})
+}
+
+func TestParseCollectorEndpoint(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		expect   collectorEndpoint
+	}{
+		{"otel-collector:4317", collectorEndpoint{grpc: true, insecure: true, host: "otel-collector:4317"}},
+		{"grpcs://otel-collector:4317", collectorEndpoint{grpc: true, host: "otel-collector:4317"}},
+		{"http://otel-collector:4318", collectorEndpoint{insecure: true, host: "otel-collector:4318"}},
+		{"https://otel.example.com/otlp/v1/traces", collectorEndpoint{host: "otel.example.com", path: "/otlp/v1/traces"}},
+	}
+
+	for _, c := range cases {
+		e := testingv2.MustValue(t, func() (*collectorEndpoint, error) {
+			return parseCollectorEndpoint(c.endpoint)
+		})
+
+		testingv2.Then(t, "scheme 决定协议与是否启用 TLS: "+c.endpoint,
+			testingv2.Expect(*e == c.expect, testingv2.Equal(true)),
+		)
+	}
+
+	_, err := parseCollectorEndpoint("tcp://otel-collector:4317")
+
+	testingv2.Then(t, "不支持的 scheme 返回错误",
+		testingv2.Expect(err != nil, testingv2.Equal(true)),
+	)
+}
+
+func TestParseCollectorHeaders(t *testing.T) {
+	headers := testingv2.MustValue(t, func() (map[string]string, error) {
+		return parseCollectorHeaders("authorization=Bearer%20token, x-scope-orgid=tenant")
+	})
+
+	testingv2.Then(t, "解析以逗号分隔且 URL 编码的请求头",
+		testingv2.Expect(headers, testingv2.Equal(map[string]string{
+			"authorization": "Bearer token",
+			"x-scope-orgid": "tenant",
+		})),
+	)
+}
+
+func TestSetLogLevel(t *testing.T) {
+	o := &Otel{LogLevel: InfoLevel}
+	_ = setup(t, o)
+
+	testingv2.Must(t, func() error {
+		return o.SetLogLevel("ListUser", DebugLevel, 0)
+	})
+	testingv2.Must(t, func() error {
+		return o.SetLogLevel("", WarnLevel, 50*time.Millisecond)
+	})
+
+	levels := o.LogLevels()
+
+	testingv2.Then(t, "按名称覆盖与全局临时级别同时生效",
+		testingv2.Expect(levels.Level, testingv2.Equal(WarnLevel)),
+		testingv2.Expect(levels.ExpiresAt != nil, testingv2.Equal(true)),
+		testingv2.Expect(levels.Loggers["ListUser"].Level, testingv2.Equal(DebugLevel)),
+		testingv2.Expect(o.enabledLevel.LevelFor("ListUser"), testingv2.Equal(logr.DebugLevel)),
+		testingv2.Expect(o.enabledLevel.LevelFor("Other"), testingv2.Equal(logr.WarnLevel)),
+	)
+
+	time.Sleep(100 * time.Millisecond)
+
+	testingv2.Must(t, func() error {
+		return o.SetLogLevel("ListUser", "", 0)
+	})
+
+	testingv2.Then(t, "临时级别到期后恢复，移除覆盖后使用全局级别",
+		testingv2.Expect(o.LogLevels(), testingv2.Equal(LogLevels{Level: InfoLevel})),
+	)
+}
+
+func TestReloadLogLevel(t *testing.T) {
+	o := &Otel{LogLevel: InfoLevel}
+	_ = setup(t, o)
+
+	testingv2.Must(t, func() error {
+		return o.SetLogLevel("", DebugLevel, 0)
+	})
+	testingv2.Must(t, func() error {
+		return o.Reload(context.Background())
+	})
+
+	testingv2.Then(t, "配置未变化时保留运行期间设置的级别",
+		testingv2.Expect(o.LogLevels().Level, testingv2.Equal(DebugLevel)),
+	)
+
+	o.LogLevel = WarnLevel
+
+	testingv2.Must(t, func() error {
+		return o.Reload(context.Background())
+	})
+
+	testingv2.Then(t, "配置变化时应用配置的级别",
+		testingv2.Expect(o.LogLevels().Level, testingv2.Equal(WarnLevel)),
+	)
+}
@@ -0,0 +1,48 @@
+package otel
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/innoai-tech/infra/pkg/otel/internal/otel"
+)
+
+// RedactedValue 为敏感值被替换后的内容。
+const RedactedValue = otel.RedactedValue
+
+// RedactRule 为自定义脱敏规则，返回替换后的值及是否命中。
+type RedactRule = otel.RedactRule
+
+// RegisterRedactKeyPattern 向全局脱敏注册表注册键名模式，命中的键对应的值整体替换。
+//
+// 脱敏作用于全部日志记录的属性与正文、上报的 span 属性，以及 HTTP 中间件记录的 URL 与请求头。
+func RegisterRedactKeyPattern(pattern *regexp.Regexp) {
+	otel.DefaultRedactor.AddKeyPattern(pattern)
+}
+
+// RegisterRedactValuePattern 向全局脱敏注册表注册值模式，值中命中的片段被替换。
+func RegisterRedactValuePattern(pattern *regexp.Regexp) {
+	otel.DefaultRedactor.AddValuePattern(pattern)
+}
+
+// RegisterRedactRule 向全局脱敏注册表注册自定义规则。
+func RegisterRedactRule(rule RedactRule) {
+	otel.DefaultRedactor.AddRule(rule)
+}
+
+// RegisterRedactCardNumbers 启用银行卡号脱敏，替换日志与 span 中通过 Luhn 校验的 13 至 19 位数字。
+//
+// 默认不启用，以免误伤恰好通过校验的订单号等长数字 ID。
+func RegisterRedactCardNumbers() {
+	otel.DefaultRedactor.AddRule(otel.RedactCardNumbers)
+}
+
+// Redact 按全局脱敏注册表返回脱敏后的值，key 可为空。
+func Redact(key string, value string) string {
+	return otel.DefaultRedactor.Redact(key, value)
+}
+
+// RedactURL 返回脱敏后的 URL 字符串，不修改 u。
+func RedactURL(u *url.URL) string {
+	return otel.DefaultRedactor.RedactURL(u)
+}
@@ -7,6 +7,19 @@ import (
 	appinfo "github.com/innoai-tech/infra/pkg/appinfo"
 )
 
+type contextLogLevelController struct{}
+
+func LogLevelControllerFromContext(ctx context.Context) (LogLevelController, bool) {
+	if v, ok := ctx.Value(contextLogLevelController{}).(LogLevelController); ok {
+		return v, true
+	}
+	return nil, false
+}
+
+func LogLevelControllerInjectContext(ctx context.Context, tpe LogLevelController) context.Context {
+	return context.WithValue(ctx, contextLogLevelController{}, tpe)
+}
+
 type contextLogProcessorRegistry struct{}
 
 func LogProcessorRegistryFromContext(ctx context.Context) (LogProcessorRegistry, bool) {
@@ -12,18 +12,94 @@ func (v *Otel) RuntimeDoc(names ...string) ([]string, bool) {
 			return []string{
 				"日志格式",
 			}, true
+		case "LogAsync":
+			return []string{
+				"启用后日志经有界队列批量异步输出，避免慢终端或管道阻塞请求",
+			}, true
+		case "LogQueueSize":
+			return []string{
+				"异步日志队列长度",
+			}, true
+		case "LogDropPolicy":
+			return []string{
+				"异步日志队列满时的处理策略",
+			}, true
+		case "LogSampleFirst":
+			return []string{
+				"每个周期内同一 (级别, 消息模板, source.func) 保留的前 N 条日志，为 0 时关闭采样",
+			}, true
+		case "LogSampleThereafter":
+			return []string{
+				"超过前 N 条后每 M 条保留 1 条，小于 0 时全部抑制",
+			}, true
+		case "LogSampleIntervalSeconds":
+			return []string{
+				"采样统计周期（秒），每个周期结束时输出被抑制日志的汇总",
+			}, true
+		case "LogSampleErrors":
+			return []string{
+				"允许对 error 日志采样，默认 error 日志总是输出",
+			}, true
 		case "TraceCollectorEndpoint":
 			return []string{
-				"设置后将启用 trace 采集",
+				"设置后将启用 trace 采集，scheme 为 grpc、grpcs、http、https，不带 scheme 时为明文 gRPC",
+			}, true
+		case "TraceSampler":
+			return []string{
+				"trace 采样策略，可选 always、never、ratio:<f>、parentbased_ratio:<f>",
+			}, true
+		case "TraceSampleRules":
+			return []string{
+				"按 http.route 的采样比例，如 /healthz=0,/api/*=0.1，优先于 TraceSampler",
+			}, true
+		case "TraceSlowThresholdMilliseconds":
+			return []string{
+				"耗时不小于该值（毫秒）的 span 总是上报，小于 0 时关闭",
+			}, true
+		case "TraceDropErrors":
+			return []string{
+				"出错的 span 按采样策略处理，默认总是上报",
+			}, true
+		case "Propagators":
+			return []string{
+				"以逗号分隔的 trace 传播格式，可选 tracecontext、baggage、b3、b3single、none",
 			}, true
 		case "MetricCollectorEndpoint":
 			return []string{
-				"指标采集器地址",
+				"指标采集器地址，格式同 TraceCollectorEndpoint",
 			}, true
 		case "MetricCollectIntervalSeconds":
 			return []string{
 				"指标采集间隔（秒）",
 			}, true
+		case "LogCollectorEndpoint":
+			return []string{
+				"设置后将日志同时上报至该采集器，格式同 TraceCollectorEndpoint",
+			}, true
+		case "CollectorTLSCAFile":
+			return []string{
+				"校验采集器证书的 CA 文件，用于 grpcs 与 https",
+			}, true
+		case "CollectorTLSCertFile":
+			return []string{
+				"上报采集器的客户端证书文件",
+			}, true
+		case "CollectorTLSKeyFile":
+			return []string{
+				"上报采集器的客户端私钥文件",
+			}, true
+		case "CollectorHeaders":
+			return []string{
+				"上报采集器的请求头，如 authorization=Bearer%20xxx，多个以逗号分隔",
+			}, true
+		case "CollectorCompression":
+			return []string{
+				"上报压缩方式，可选 none、gzip",
+			}, true
+		case "CollectorTimeoutSeconds":
+			return []string{
+				"上报超时（秒）",
+			}, true
 
 		}
 